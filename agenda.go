@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"aliorToDoBot/src/dateparse"
+	"aliorToDoBot/src/db"
+	gorm_models2 "aliorToDoBot/src/db/gorm_models"
+)
+
+// ---- Функционал расписания на день, неделю и месяц ----
+
+// Периоды расписания
+const (
+	agendaDay   = "day"
+	agendaWeek  = "week"
+	agendaMonth = "month"
+)
+
+// Названия месяцев для заголовков расписания и календаря
+var monthNames = []string{"Январь", "Февраль", "Март", "Апрель", "Май", "Июнь",
+	"Июль", "Август", "Сентябрь", "Октябрь", "Ноябрь", "Декабрь"}
+
+// agendaRange возвращает границы [from, to) периода, сдвинутого на offset периодов от текущего.
+// Неделя начинается с понедельника.
+func agendaRange(period string, offset int, now time.Time) (time.Time, time.Time) {
+	year, month, day := now.Date()
+	today := time.Date(year, month, day, 0, 0, 0, 0, now.Location())
+
+	switch period {
+	case agendaWeek:
+		monday := today.AddDate(0, 0, -(int(today.Weekday())+6)%7+7*offset)
+		return monday, monday.AddDate(0, 0, 7)
+	case agendaMonth:
+		first := time.Date(year, month+time.Month(offset), 1, 0, 0, 0, 0, now.Location())
+		return first, first.AddDate(0, 1, 0)
+	default:
+		start := today.AddDate(0, 0, offset)
+		return start, start.AddDate(0, 0, 1)
+	}
+}
+
+// agendaTitle возвращает заголовок периода расписания
+func agendaTitle(period string, from, to time.Time) string {
+	switch period {
+	case agendaWeek:
+		return fmt.Sprintf("Неделя %s — %s", from.Format("02.01"), to.AddDate(0, 0, -1).Format("02.01.2006"))
+	case agendaMonth:
+		return fmt.Sprintf("%s %d", monthNames[from.Month()-1], from.Year())
+	default:
+		return dateparse.Describe(from, false)
+	}
+}
+
+// occurrenceDay возвращает день повторения в часовом поясе loc.
+// Дата мероприятия на весь день не зависит от зрителя и берётся в часовом поясе мероприятия.
+func occurrenceDay(event gorm_models2.Event, loc *time.Location) time.Time {
+	start := event.DatetimeStart.In(loc)
+	if event.IsAllDay {
+		start = event.DatetimeStart.In(eventLocation(event))
+	}
+	year, month, day := start.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, loc)
+}
+
+// dayOccurrences раскладывает повторения мероприятий по дням периода [from, to) в часовом поясе loc.
+// Идущие мероприятия, начавшиеся раньше периода, попадают в его первый день.
+// Внутри дня мероприятия на весь день идут первыми, остальные — по времени начала.
+func dayOccurrences(events []gorm_models2.Event, loc *time.Location, from, to time.Time) ([]time.Time, map[time.Time][]occurrence) {
+	exceptions := loadExceptions(events)
+	byDay := make(map[time.Time][]occurrence)
+	var days []time.Time
+	for _, event := range events {
+		for _, occ := range eventOccurrences(event, exceptions[event.IDEvent], from.AddDate(0, 0, -1), to) {
+			day := occurrenceDay(occ.Event, loc)
+			if day.Before(from) {
+				if _, end := occurrenceBounds(occ.Event); !end.After(from) {
+					continue
+				}
+				day = from
+			}
+			if !day.Before(to) {
+				continue
+			}
+			if _, ok := byDay[day]; !ok {
+				days = append(days, day)
+			}
+			byDay[day] = append(byDay[day], occ)
+		}
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+
+	for _, occurrences := range byDay {
+		sort.SliceStable(occurrences, func(i, j int) bool {
+			a, b := occurrences[i].Event, occurrences[j].Event
+			if a.IsAllDay != b.IsAllDay {
+				return a.IsAllDay
+			}
+			return a.DatetimeStart.Before(b.DatetimeStart)
+		})
+	}
+	return days, byDay
+}
+
+// agendaView формирует расписание пользователя на период: мероприятия по дням в порядке начала.
+// Идущие мероприятия, начавшиеся раньше периода, показываются в его первый день.
+func agendaView(chatID int64, period string, offset int) (string, tgbotapi.InlineKeyboardMarkup, error) {
+	ctx := context.Background()
+
+	var user gorm_models2.User
+	if err := db.DB.Where("id_chat = ?", chatID).First(&user).Error; err != nil {
+		return "", tgbotapi.InlineKeyboardMarkup{}, fmt.Errorf("пользователь не найден")
+	}
+	loc := loadLocation(user.TimeZone)
+	from, to := agendaRange(period, offset, time.Now().In(loc))
+
+	// Начало сдвинуто на сутки, чтобы не потерять мероприятия на весь день из других часовых поясов
+	events, err := provider.GetUserEventsInRange(ctx, user.IDUser, from.AddDate(0, 0, -1), to)
+	if err != nil {
+		return "", tgbotapi.InlineKeyboardMarkup{}, err
+	}
+	groups, err := provider.GetUserGroups(ctx, chatID)
+	if err != nil {
+		return "", tgbotapi.InlineKeyboardMarkup{}, err
+	}
+	groupMap := make(map[int64]string)
+	for _, group := range groups {
+		groupMap[group.IDGroup] = group.GroupName
+	}
+
+	days, byDay := dayOccurrences(events, loc, from, to)
+
+	var text strings.Builder
+	text.WriteString("📆 *" + agendaTitle(period, from, to) + "*\n")
+	if len(days) == 0 {
+		text.WriteString("\nМероприятий нет.")
+	} else if period == agendaDay {
+		text.WriteString("\n")
+	}
+	var cards [][]tgbotapi.InlineKeyboardButton
+	// Неделя или месяц могут не поместиться в одно сообщение: лишние строки отбрасываются с пометкой
+days:
+	for _, day := range days {
+		occurrences := byDay[day]
+		if period != agendaDay {
+			if !appendPageBlock(&text, "\n*"+dateparse.Describe(day, false)+"*\n") {
+				break
+			}
+		}
+		for _, occ := range occurrences {
+			when := "весь день"
+			if !occ.Event.IsAllDay {
+				when = occ.Event.DatetimeStart.In(loc).Format("15:04")
+			}
+			line := fmt.Sprintf("• %s — %s (%s)", when,
+				tgbotapi.EscapeText(tgbotapi.ModeMarkdown, occ.Event.NameEvent),
+				tgbotapi.EscapeText(tgbotapi.ModeMarkdown, groupMap[occ.Event.IDGroup]))
+			if occ.Event.Status == gorm_models2.EventStatusPostponed {
+				line += " — перенесено"
+			}
+			if !appendPageBlock(&text, line+"\n") {
+				break days
+			}
+
+			if period == agendaDay {
+				cards = append(cards, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(
+					"📅 "+occ.Event.NameEvent, fmt.Sprintf("event_card_%d", occ.Event.IDEvent))))
+			}
+		}
+	}
+
+	periodButton := func(label, p string) tgbotapi.InlineKeyboardButton {
+		if p == period {
+			label = "• " + label
+		}
+		return tgbotapi.NewInlineKeyboardButtonData(label, fmt.Sprintf("agenda_%s_0", p))
+	}
+	rows := append(cards,
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("◀️", fmt.Sprintf("agenda_%s_%d", period, offset-1)),
+			tgbotapi.NewInlineKeyboardButtonData("Сейчас", fmt.Sprintf("agenda_%s_0", period)),
+			tgbotapi.NewInlineKeyboardButtonData("▶️", fmt.Sprintf("agenda_%s_%d", period, offset+1)),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			periodButton("День", agendaDay),
+			periodButton("Неделя", agendaWeek),
+			periodButton("Месяц", agendaMonth),
+		),
+	)
+	return text.String(), tgbotapi.NewInlineKeyboardMarkup(rows...), nil
+}
+
+// viewAgenda отправляет расписание на текущий период
+func viewAgenda(bot *tgbotapi.BotAPI, chatID int64, period string) {
+	UpdateEventStatuses(db.DB)
+	text, keyboard, err := agendaView(chatID, period, 0)
+	if err != nil {
+		log.Printf("Ошибка получения расписания пользователя %d: %v", chatID, err)
+		bot.Send(tgbotapi.NewMessage(chatID, "Не удалось получить расписание: "+err.Error()))
+		return
+	}
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = keyboard
+	if _, err = bot.Send(msg); err != nil {
+		log.Printf("Ошибка отправки сообщения: %v", err)
+	}
+}
+
+// handleAgendaCallback переключает период расписания на месте
+func handleAgendaCallback(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+
+	// Данные вида agenda_<период>_<сдвиг>
+	parts := strings.SplitN(strings.TrimPrefix(callback.Data, "agenda_"), "_", 2)
+	if len(parts) != 2 {
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Неизвестное действие"))
+		return
+	}
+	offset, err := strconv.Atoi(parts[1])
+	if err != nil {
+		log.Printf("Некорректные данные кнопки расписания: %s", callback.Data)
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Неизвестное действие"))
+		return
+	}
+
+	text, keyboard, err := agendaView(chatID, parts[0], offset)
+	if err != nil {
+		log.Printf("Ошибка получения расписания пользователя %d: %v", chatID, err)
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Не удалось получить расписание: "+err.Error()))
+		return
+	}
+	bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+
+	edit := tgbotapi.NewEditMessageTextAndMarkup(chatID, callback.Message.MessageID, text, keyboard)
+	edit.ParseMode = "Markdown"
+	if _, err = bot.Send(edit); err != nil {
+		log.Printf("Ошибка обновления расписания: %v", err)
+	}
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"aliorToDoBot/src/db"
+	gorm_models2 "aliorToDoBot/src/db/gorm_models"
+)
+
+// ---- Функционал вложений мероприятий ----
+
+var viewedEvent = make(map[int64]openedEvent) // Последнее открытое мероприятие: к нему прикрепляются присланные файлы
+
+// openedEvent мероприятие, открытое пользователем, и время его открытия
+type openedEvent struct {
+	IDEvent  int64
+	OpenedAt time.Time
+}
+
+// rememberViewedEvent запоминает открытое мероприятие для прикрепления файлов
+func rememberViewedEvent(chatID int64, eventID int64) {
+	viewedEvent[chatID] = openedEvent{IDEvent: eventID, OpenedAt: time.Now()}
+}
+
+// currentViewedEvent возвращает открытое мероприятие, если оно открыто не дольше sessionTTL назад.
+// Иначе файл, присланный много позже, прикрепился бы к давно забытому мероприятию.
+func currentViewedEvent(chatID int64) (int64, bool) {
+	opened, ok := viewedEvent[chatID]
+	if !ok {
+		return 0, false
+	}
+	if time.Since(opened.OpenedAt) > sessionTTL {
+		delete(viewedEvent, chatID)
+		return 0, false
+	}
+	return opened.IDEvent, true
+}
+
+// attachmentsButton возвращает кнопку просмотра вложений мероприятия
+func attachmentsButton(event gorm_models2.Event) tgbotapi.InlineKeyboardButton {
+	label := "📎 Вложения"
+	if len(event.Attachments) > 0 {
+		label += fmt.Sprintf(" (%d)", len(event.Attachments))
+	}
+	return tgbotapi.NewInlineKeyboardButtonData(label, fmt.Sprintf("attach_%d", event.IDEvent))
+}
+
+// viewAttachments заново отправляет файлы, прикреплённые к мероприятию
+func viewAttachments(bot *tgbotapi.BotAPI, chatID int64, eventID int64) {
+	attachments, err := provider.GetEventAttachments(context.Background(), chatID, eventID)
+	if err != nil {
+		log.Printf("Ошибка получения вложений мероприятия ID %d: %v", eventID, err)
+		bot.Send(tgbotapi.NewMessage(chatID, "Не удалось получить вложения: "+err.Error()))
+		return
+	}
+	rememberViewedEvent(chatID, eventID)
+
+	if len(attachments) == 0 {
+		bot.Send(tgbotapi.NewMessage(chatID, "Вложений пока нет. Чтобы прикрепить документ или фото, "+
+			"отправьте его в чат, пока открыто это мероприятие."))
+		return
+	}
+
+	for _, attachment := range attachments {
+		keyboard := tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🗑 Открепить", fmt.Sprintf("attach_del_%d", attachment.IDAttachment)),
+		))
+
+		var msg tgbotapi.Chattable
+		if attachment.Kind == gorm_models2.AttachmentPhoto {
+			photo := tgbotapi.NewPhoto(chatID, tgbotapi.FileID(attachment.FileID))
+			photo.Caption = attachment.Caption
+			photo.ReplyMarkup = keyboard
+			msg = photo
+		} else {
+			document := tgbotapi.NewDocument(chatID, tgbotapi.FileID(attachment.FileID))
+			document.Caption = attachment.Caption
+			document.ReplyMarkup = keyboard
+			msg = document
+		}
+		if _, err = bot.Send(msg); err != nil {
+			log.Printf("Ошибка отправки вложения ID %d: %v", attachment.IDAttachment, err)
+		}
+	}
+}
+
+// handleAttachmentCallback обрабатывает кнопки вложений
+func handleAttachmentCallback(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+	data := callback.Data
+
+	prefix := "attach_"
+	if strings.HasPrefix(data, "attach_del_") {
+		prefix = "attach_del_"
+	}
+	id, err := strconv.ParseInt(strings.TrimPrefix(data, prefix), 10, 64)
+	if err != nil {
+		log.Printf("Некорректные данные кнопки вложений: %s", data)
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Неизвестное действие"))
+		return
+	}
+
+	if prefix == "attach_" {
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		viewAttachments(bot, chatID, id)
+		return
+	}
+
+	if _, err = provider.DeleteEventAttachment(context.Background(), chatID, id); err != nil {
+		log.Printf("Ошибка удаления вложения ID %d: %v", id, err)
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Не удалось открепить файл: "+err.Error()))
+		return
+	}
+	bot.Request(tgbotapi.NewCallback(callback.ID, "Файл откреплён."))
+	edit := tgbotapi.NewEditMessageReplyMarkup(chatID, callback.Message.MessageID, tgbotapi.InlineKeyboardMarkup{
+		InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{},
+	})
+	if _, err = bot.Send(edit); err != nil {
+		log.Printf("Ошибка обновления кнопок вложения: %v", err)
+	}
+}
+
+// handleAttachmentMessage прикрепляет присланный документ или фото к редактируемому
+// или последнему открытому мероприятию
+func handleAttachmentMessage(bot *tgbotapi.BotAPI, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+
+	eventID, ok := currentViewedEvent(chatID)
+	if event, editing := editEvent[chatID]; editing {
+		eventID, ok = event.IDEvent, true
+	}
+	if !ok {
+		bot.Send(tgbotapi.NewMessage(chatID, "Чтобы прикрепить файл, сначала откройте мероприятие."))
+		return
+	}
+
+	attachment := gorm_models2.EventAttachment{IDEvent: eventID, Caption: message.Caption}
+	if message.Document != nil {
+		attachment.Kind = gorm_models2.AttachmentDocument
+		attachment.FileID = message.Document.FileID
+		attachment.FileUniqueID = message.Document.FileUniqueID
+		attachment.FileName = message.Document.FileName
+		attachment.MimeType = message.Document.MimeType
+		attachment.FileSize = int64(message.Document.FileSize)
+	} else {
+		// Telegram присылает фото в нескольких размерах, последний — самый большой
+		photo := message.Photo[len(message.Photo)-1]
+		attachment.Kind = gorm_models2.AttachmentPhoto
+		attachment.FileID = photo.FileID
+		attachment.FileUniqueID = photo.FileUniqueID
+		attachment.FileSize = int64(photo.FileSize)
+	}
+
+	if err := provider.AddEventAttachment(context.Background(), chatID, attachment); err != nil {
+		log.Printf("Ошибка прикрепления файла к мероприятию ID %d: %v", eventID, err)
+		bot.Send(tgbotapi.NewMessage(chatID, "Не удалось прикрепить файл: "+err.Error()))
+		return
+	}
+
+	var event gorm_models2.Event
+	if err := db.DB.First(&event, eventID).Error; err != nil {
+		log.Printf("Ошибка получения мероприятия: %v", err)
+	}
+	msg := tgbotapi.NewMessage(chatID, "📎 Файл прикреплён к мероприятию «"+event.NameEvent+"».")
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("📎 Вложения", fmt.Sprintf("attach_%d", eventID)),
+	))
+	if _, err := bot.Send(msg); err != nil {
+		log.Printf("Ошибка отправки сообщения: %v", err)
+	}
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"unicode"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	gorm_models2 "aliorToDoBot/src/db/gorm_models"
+)
+
+// ---- Функционал категорий мероприятий ----
+
+var categoryTarget = make(map[int64]int64) // ID группы для новой категории или ID переименовываемой категории
+
+// categoryLabel возвращает название категории вместе с эмодзи
+func categoryLabel(category gorm_models2.Category) string {
+	if category.Emoji == "" {
+		return category.Name
+	}
+	return category.Emoji + " " + category.Name
+}
+
+// matchCategory ищет категорию по нажатой кнопке или введённому названию
+func matchCategory(categories []gorm_models2.Category, text string) (gorm_models2.Category, bool) {
+	text = strings.TrimSpace(text)
+	for _, category := range categories {
+		if text == categoryLabel(category) || strings.EqualFold(text, category.Name) {
+			return category, true
+		}
+	}
+	return gorm_models2.Category{}, false
+}
+
+// parseCategoryInput разбирает ввод вида "🏃 Спорт": эмодзи в начале необязателен
+func parseCategoryInput(text string) (string, string) {
+	fields := strings.Fields(text)
+	if len(fields) > 1 && !strings.ContainsFunc(fields[0], func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsDigit(r)
+	}) {
+		return strings.Join(fields[1:], " "), fields[0]
+	}
+	return strings.Join(fields, " "), ""
+}
+
+// sendCategoryPrompt предлагает выбрать одну из категорий группы кнопками
+func sendCategoryPrompt(bot *tgbotapi.BotAPI, chatID int64, idGroup int64, text string) {
+	categories, err := provider.GetGroupCategories(context.Background(), idGroup)
+	if err != nil {
+		log.Printf("Ошибка получения категорий группы %d: %v", idGroup, err)
+		bot.Send(tgbotapi.NewMessage(chatID, "Ошибка при получении категорий группы."))
+		return
+	}
+	if len(categories) == 0 {
+		bot.Send(tgbotapi.NewMessage(chatID, "В группе нет категорий. Администратор может добавить их в меню «Группы» → «Категории»."))
+		return
+	}
+
+	var keyboard [][]tgbotapi.KeyboardButton
+	var row []tgbotapi.KeyboardButton
+	for _, category := range categories {
+		row = append(row, tgbotapi.NewKeyboardButton(categoryLabel(category)))
+		if len(row) == 3 {
+			keyboard = append(keyboard, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		keyboard = append(keyboard, row)
+	}
+	keyboard = append(keyboard, []tgbotapi.KeyboardButton{tgbotapi.NewKeyboardButton("Главное меню")})
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyMarkup = tgbotapi.ReplyKeyboardMarkup{
+		Keyboard:       keyboard,
+		ResizeKeyboard: true,
+	}
+	if _, err = bot.Send(msg); err != nil {
+		log.Printf("Ошибка отправки сообщения: %v", err)
+	}
+}
+
+// viewCategoryGroups предлагает выбрать группу, категориями которой пользователь хочет управлять
+func viewCategoryGroups(bot *tgbotapi.BotAPI, chatID int64) {
+	groups, err := provider.GetAdminGroups(context.Background(), chatID)
+	if err != nil {
+		log.Printf("Ошибка получения групп пользователя %d: %v", chatID, err)
+		bot.Send(tgbotapi.NewMessage(chatID, "Ошибка при получении ваших групп."))
+		return
+	}
+	if len(groups) == 0 {
+		bot.Send(tgbotapi.NewMessage(chatID, "Управлять категориями может только администратор группы."))
+		return
+	}
+
+	var inlineKeyboard [][]tgbotapi.InlineKeyboardButton
+	for _, group := range groups {
+		inlineKeyboard = append(inlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(group.GroupName, fmt.Sprintf("cat_group_%d", group.IDGroup)),
+		))
+	}
+	msg := tgbotapi.NewMessage(chatID, "Выберите группу для настройки категорий:")
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(inlineKeyboard...)
+	bot.Send(msg)
+}
+
+// viewGroupCategories показывает категории группы с кнопками изменения и удаления
+func viewGroupCategories(bot *tgbotapi.BotAPI, chatID int64, idGroup int64) {
+	categories, err := provider.GetGroupCategories(context.Background(), idGroup)
+	if err != nil {
+		log.Printf("Ошибка получения категорий группы %d: %v", idGroup, err)
+		bot.Send(tgbotapi.NewMessage(chatID, "Ошибка при получении категорий группы."))
+		return
+	}
+
+	var inlineKeyboard [][]tgbotapi.InlineKeyboardButton
+	for _, category := range categories {
+		inlineKeyboard = append(inlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✏️ "+categoryLabel(category), fmt.Sprintf("cat_edit_%d", category.IDCategory)),
+			tgbotapi.NewInlineKeyboardButtonData("🗑", fmt.Sprintf("cat_del_%d", category.IDCategory)),
+		))
+	}
+	inlineKeyboard = append(inlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("➕ Добавить категорию", fmt.Sprintf("cat_add_%d", idGroup)),
+	))
+
+	text := "Категории группы. Нажмите на категорию, чтобы переименовать её:"
+	if len(categories) == 0 {
+		text = "В группе пока нет категорий."
+	}
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(inlineKeyboard...)
+	bot.Send(msg)
+}
+
+// handleCategoryCallback обрабатывает кнопки управления категориями
+func handleCategoryCallback(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+	data := callback.Data
+
+	var prefix string
+	for _, p := range []string{"cat_group_", "cat_add_", "cat_edit_", "cat_del_"} {
+		if strings.HasPrefix(data, p) {
+			prefix = p
+			break
+		}
+	}
+	id, err := strconv.ParseInt(strings.TrimPrefix(data, prefix), 10, 64)
+	if prefix == "" || err != nil {
+		log.Printf("Некорректные данные кнопки категории: %s", data)
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Неизвестное действие"))
+		return
+	}
+
+	switch prefix {
+	case "cat_group_":
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		viewGroupCategories(bot, chatID, id)
+
+	case "cat_add_":
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		categoryTarget[chatID] = id
+		userSteps[chatID] = "creating_category"
+		sendInputPrompt(bot, chatID, "Введите название новой категории. Можно добавить эмодзи в начале, например: 🏃 Спорт")
+
+	case "cat_edit_":
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		categoryTarget[chatID] = id
+		userSteps[chatID] = "renaming_category"
+		sendInputPrompt(bot, chatID, "Введите новое название категории. Можно добавить эмодзи в начале, например: 🏃 Спорт")
+
+	case "cat_del_":
+		category, err := provider.GetCategory(context.Background(), id)
+		if err != nil {
+			log.Printf("Ошибка получения категории ID %d: %v", id, err)
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Категория не найдена."))
+			return
+		}
+		if err = provider.DeleteCategory(context.Background(), chatID, id); err != nil {
+			log.Printf("Ошибка удаления категории ID %d: %v", id, err)
+			bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+			bot.Send(tgbotapi.NewMessage(chatID, "Не удалось удалить категорию: "+err.Error()))
+			return
+		}
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Категория удалена."))
+		viewGroupCategories(bot, chatID, category.IDGroup)
+	}
+}
+
+// sendInputPrompt запрашивает ввод текста, оставляя только кнопку возврата в главное меню
+func sendInputPrompt(bot *tgbotapi.BotAPI, chatID int64, text string) {
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyMarkup = tgbotapi.ReplyKeyboardMarkup{
+		Keyboard: [][]tgbotapi.KeyboardButton{
+			{tgbotapi.NewKeyboardButton("Главное меню")},
+		},
+		ResizeKeyboard: true,
+	}
+	bot.Send(msg)
+}
+
+// handleCategoryInput сохраняет новую категорию или новое название существующей
+func handleCategoryInput(bot *tgbotapi.BotAPI, chatID int64, text string) {
+	target, ok := categoryTarget[chatID]
+	if !ok || text == "Главное меню" {
+		delete(categoryTarget, chatID)
+		delete(userSteps, chatID)
+		sendMainMenu(bot, chatID)
+		return
+	}
+
+	name, emoji := parseCategoryInput(text)
+	ctx := context.Background()
+
+	var (
+		idGroup int64
+		err     error
+	)
+	switch userSteps[chatID] {
+	case "creating_category":
+		idGroup = target
+		err = provider.CreateCategory(ctx, chatID, idGroup, name, emoji)
+	case "renaming_category":
+		var category gorm_models2.Category
+		if category, err = provider.GetCategory(ctx, target); err == nil {
+			idGroup = category.IDGroup
+			err = provider.UpdateCategory(ctx, chatID, target, name, emoji)
+		}
+	}
+	if err != nil {
+		log.Printf("Ошибка сохранения категории: %v", err)
+		bot.Send(tgbotapi.NewMessage(chatID, "Не удалось сохранить категорию: "+err.Error()))
+		return
+	}
+
+	delete(categoryTarget, chatID)
+	delete(userSteps, chatID)
+	bot.Send(tgbotapi.NewMessage(chatID, "Категория сохранена."))
+	sendGroupsMenu(bot, chatID)
+	viewGroupCategories(bot, chatID, idGroup)
+}
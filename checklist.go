@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"aliorToDoBot/src/db"
+	gorm_models2 "aliorToDoBot/src/db/gorm_models"
+)
+
+// ---- Функционал чек-листов мероприятий ----
+
+var checklistTarget = make(map[int64]int64) // ID мероприятия, в чек-лист которого добавляются пункты
+
+// checklistProgress возвращает прогресс чек-листа в виде "3/5"
+func checklistProgress(items []gorm_models2.ChecklistItem) string {
+	done := 0
+	for _, item := range items {
+		if item.IsDone {
+			done++
+		}
+	}
+	return fmt.Sprintf("%d/%d", done, len(items))
+}
+
+// checklistView формирует текст и кнопки чек-листа мероприятия.
+// Нажатие на пункт отмечает его выполненным или снимает отметку.
+func checklistView(chatID int64, eventID int64) (string, tgbotapi.InlineKeyboardMarkup, error) {
+	ctx := context.Background()
+
+	items, err := provider.GetChecklist(ctx, chatID, eventID)
+	if err != nil {
+		return "", tgbotapi.InlineKeyboardMarkup{}, err
+	}
+	var event gorm_models2.Event
+	if err = db.DB.First(&event, eventID).Error; err != nil {
+		return "", tgbotapi.InlineKeyboardMarkup{}, fmt.Errorf("мероприятие не найдено")
+	}
+	members, err := provider.GetGroupMembers(ctx, event.IDGroup)
+	if err != nil {
+		return "", tgbotapi.InlineKeyboardMarkup{}, err
+	}
+	names := make(map[int64]string)
+	for _, member := range members {
+		names[member.IDUser] = member.UserName
+	}
+
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("Чек-лист «%s»: %s\n", event.NameEvent, checklistProgress(items)))
+	if len(items) == 0 {
+		text.WriteString("\nПунктов пока нет.")
+	}
+
+	var inlineKeyboard [][]tgbotapi.InlineKeyboardButton
+	for _, item := range items {
+		mark := "⬜"
+		if item.IsDone {
+			mark = "✅"
+		}
+		line := "\n" + mark + " " + item.Text
+		if item.IsDone && item.IDCheckedBy != nil {
+			line += " — " + names[*item.IDCheckedBy]
+		}
+		text.WriteString(line)
+
+		inlineKeyboard = append(inlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(mark+" "+item.Text, fmt.Sprintf("checklist_toggle_%d", item.IDItem)),
+			tgbotapi.NewInlineKeyboardButtonData("🗑", fmt.Sprintf("checklist_del_%d", item.IDItem)),
+		))
+	}
+	inlineKeyboard = append(inlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("➕ Добавить пункты", fmt.Sprintf("checklist_add_%d", eventID)),
+	))
+	return text.String(), tgbotapi.NewInlineKeyboardMarkup(inlineKeyboard...), nil
+}
+
+// viewChecklist отправляет чек-лист мероприятия
+func viewChecklist(bot *tgbotapi.BotAPI, chatID int64, eventID int64) {
+	text, keyboard, err := checklistView(chatID, eventID)
+	if err != nil {
+		log.Printf("Ошибка получения чек-листа мероприятия ID %d: %v", eventID, err)
+		bot.Send(tgbotapi.NewMessage(chatID, "Не удалось открыть чек-лист: "+err.Error()))
+		return
+	}
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyMarkup = keyboard
+	if _, err = bot.Send(msg); err != nil {
+		log.Printf("Ошибка отправки сообщения: %v", err)
+	}
+}
+
+// refreshChecklist обновляет сообщение с чек-листом на месте
+func refreshChecklist(bot *tgbotapi.BotAPI, message *tgbotapi.Message, eventID int64) {
+	text, keyboard, err := checklistView(message.Chat.ID, eventID)
+	if err != nil {
+		log.Printf("Ошибка получения чек-листа мероприятия ID %d: %v", eventID, err)
+		return
+	}
+	edit := tgbotapi.NewEditMessageTextAndMarkup(message.Chat.ID, message.MessageID, text, keyboard)
+	if _, err = bot.Send(edit); err != nil {
+		log.Printf("Ошибка обновления чек-листа: %v", err)
+	}
+}
+
+// handleChecklistCallback обрабатывает кнопки чек-листа
+func handleChecklistCallback(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+	data := callback.Data
+
+	var prefix string
+	for _, p := range []string{"checklist_toggle_", "checklist_del_", "checklist_add_", "checklist_"} {
+		if strings.HasPrefix(data, p) {
+			prefix = p
+			break
+		}
+	}
+	id, err := strconv.ParseInt(strings.TrimPrefix(data, prefix), 10, 64)
+	if err != nil {
+		log.Printf("Некорректные данные кнопки чек-листа: %s", data)
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Неизвестное действие"))
+		return
+	}
+
+	switch prefix {
+	case "checklist_":
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		viewChecklist(bot, chatID, id)
+
+	case "checklist_toggle_":
+		item, err := provider.ToggleChecklistItem(context.Background(), chatID, id)
+		if err != nil {
+			log.Printf("Ошибка отметки пункта чек-листа ID %d: %v", id, err)
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Не удалось отметить пункт: "+err.Error()))
+			return
+		}
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		refreshChecklist(bot, callback.Message, item.IDEvent)
+
+	case "checklist_del_":
+		item, err := provider.DeleteChecklistItem(context.Background(), chatID, id)
+		if err != nil {
+			log.Printf("Ошибка удаления пункта чек-листа ID %d: %v", id, err)
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Не удалось удалить пункт: "+err.Error()))
+			return
+		}
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Пункт удалён."))
+		refreshChecklist(bot, callback.Message, item.IDEvent)
+
+	case "checklist_add_":
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		checklistTarget[chatID] = id
+		userSteps[chatID] = "adding_checklist_items"
+		msg := tgbotapi.NewMessage(chatID, "Введите пункт чек-листа. Чтобы добавить несколько, пишите каждый с новой строки:")
+		msg.ReplyMarkup = tgbotapi.ReplyKeyboardMarkup{
+			Keyboard: [][]tgbotapi.KeyboardButton{
+				{tgbotapi.NewKeyboardButton("Главное меню")},
+			},
+			ResizeKeyboard: true,
+		}
+		bot.Send(msg)
+	}
+}
+
+// handleChecklistInput добавляет введённые пункты в чек-лист: по одному на строку
+func handleChecklistInput(bot *tgbotapi.BotAPI, chatID int64, text string) {
+	eventID, ok := checklistTarget[chatID]
+	if !ok || text == "Главное меню" {
+		delete(checklistTarget, chatID)
+		delete(userSteps, chatID)
+		sendMainMenu(bot, chatID)
+		return
+	}
+
+	if err := provider.AddChecklistItems(context.Background(), chatID, eventID, strings.Split(text, "\n")); err != nil {
+		log.Printf("Ошибка добавления пунктов чек-листа мероприятия ID %d: %v", eventID, err)
+		bot.Send(tgbotapi.NewMessage(chatID, "Не удалось добавить пункты: "+err.Error()))
+		return
+	}
+
+	delete(checklistTarget, chatID)
+	delete(userSteps, chatID)
+	sendEventsMenu(bot, chatID)
+	viewChecklist(bot, chatID, eventID)
+}
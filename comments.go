@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"aliorToDoBot/src/db"
+	gorm_models2 "aliorToDoBot/src/db/gorm_models"
+)
+
+// ---- Функционал комментариев к мероприятиям ----
+
+const commentsShown = 20 // Сколько последних комментариев показывать в обсуждении
+
+const olderCommentsNote = "\n…более ранние комментарии не поместились в сообщение.\n"
+
+var commentTarget = make(map[int64]int64) // ID мероприятия, к которому пользователь пишет комментарий
+
+// commentsButton возвращает кнопку перехода к обсуждению мероприятия
+func commentsButton(event gorm_models2.Event) tgbotapi.InlineKeyboardButton {
+	label := "💬 Комментарии"
+	if len(event.Comments) > 0 {
+		label += fmt.Sprintf(" (%d)", len(event.Comments))
+	}
+	return tgbotapi.NewInlineKeyboardButtonData(label, fmt.Sprintf("comment_%d", event.IDEvent))
+}
+
+// formatComment форматирует комментарий для показа в часовом поясе loc
+func formatComment(comment gorm_models2.EventComment, loc *time.Location) string {
+	return fmt.Sprintf("*%s*, %s:\n%s",
+		tgbotapi.EscapeText(tgbotapi.ModeMarkdown, comment.User.UserName),
+		comment.CreatedAt.In(loc).Format("02.01 15:04"),
+		tgbotapi.EscapeText(tgbotapi.ModeMarkdown, comment.Text))
+}
+
+// viewComments показывает последние комментарии к мероприятию и кнопку нового комментария
+func viewComments(bot *tgbotapi.BotAPI, chatID int64, eventID int64) {
+	comments, err := provider.GetEventComments(context.Background(), chatID, eventID, commentsShown)
+	if err != nil {
+		log.Printf("Ошибка получения комментариев мероприятия ID %d: %v", eventID, err)
+		bot.Send(tgbotapi.NewMessage(chatID, "Не удалось открыть комментарии: "+err.Error()))
+		return
+	}
+
+	var event gorm_models2.Event
+	if err = db.DB.First(&event, eventID).Error; err != nil {
+		log.Printf("Ошибка получения мероприятия: %v", err)
+	}
+
+	var text strings.Builder
+	text.WriteString("💬 Обсуждение «" + tgbotapi.EscapeText(tgbotapi.ModeMarkdown, event.NameEvent) + "»\n")
+	if len(comments) == 0 {
+		text.WriteString("\nКомментариев пока нет.")
+	}
+
+	// Набираем комментарии от новых к старым, пока сообщение укладывается в ограничение Telegram:
+	// старые комментарии, которые не поместились, заменяются пометкой
+	loc := userLocation(chatID)
+	blocks := make([]string, 0, len(comments))
+	length := messageLength(text.String()) + messageLength(olderCommentsNote)
+	for i := len(comments) - 1; i >= 0; i-- {
+		block := "\n" + formatComment(comments[i], loc) + "\n"
+		if length+messageLength(block) > maxMessageLength {
+			break
+		}
+		length += messageLength(block)
+		blocks = append(blocks, block)
+	}
+	if len(blocks) < len(comments) {
+		text.WriteString(olderCommentsNote)
+	}
+	for i := len(blocks) - 1; i >= 0; i-- {
+		text.WriteString(blocks[i])
+	}
+
+	msg := tgbotapi.NewMessage(chatID, text.String())
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("✍️ Написать", fmt.Sprintf("comment_add_%d", eventID)),
+	))
+	if _, err = bot.Send(msg); err != nil {
+		log.Printf("Ошибка отправки сообщения: %v", err)
+	}
+}
+
+// handleCommentCallback обрабатывает кнопки обсуждения мероприятия
+func handleCommentCallback(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+	data := callback.Data
+
+	prefix := "comment_"
+	if strings.HasPrefix(data, "comment_add_") {
+		prefix = "comment_add_"
+	}
+	eventID, err := strconv.ParseInt(strings.TrimPrefix(data, prefix), 10, 64)
+	if err != nil {
+		log.Printf("Некорректные данные кнопки комментариев: %s", data)
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Неизвестное действие"))
+		return
+	}
+	bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+
+	if prefix == "comment_" {
+		viewComments(bot, chatID, eventID)
+		return
+	}
+	commentTarget[chatID] = eventID
+	userSteps[chatID] = "adding_event_comment"
+	sendInputPrompt(bot, chatID, "Введите комментарий:")
+}
+
+// handleCommentInput сохраняет комментарий и рассылает его остальным участникам группы
+func handleCommentInput(bot *tgbotapi.BotAPI, chatID int64, text string) {
+	eventID, ok := commentTarget[chatID]
+	if !ok || text == "Главное меню" {
+		delete(commentTarget, chatID)
+		delete(userSteps, chatID)
+		sendMainMenu(bot, chatID)
+		return
+	}
+
+	comment, err := provider.AddEventComment(context.Background(), chatID, eventID, text)
+	if err != nil {
+		log.Printf("Ошибка добавления комментария к мероприятию ID %d: %v", eventID, err)
+		bot.Send(tgbotapi.NewMessage(chatID, "Не удалось добавить комментарий: "+err.Error()))
+		return
+	}
+
+	delete(commentTarget, chatID)
+	delete(userSteps, chatID)
+	notifyComment(bot, chatID, comment)
+	sendEventsMenu(bot, chatID)
+	viewComments(bot, chatID, eventID)
+}
+
+// notifyComment сообщает о новом комментарии всем участникам группы, кроме автора
+func notifyComment(bot *tgbotapi.BotAPI, authorChatID int64, comment gorm_models2.EventComment) {
+	var event gorm_models2.Event
+	if err := db.DB.First(&event, comment.IDEvent).Error; err != nil {
+		log.Printf("Ошибка получения мероприятия ID %d: %v", comment.IDEvent, err)
+		return
+	}
+	members, err := provider.GetGroupMembers(context.Background(), event.IDGroup)
+	if err != nil {
+		log.Printf("Ошибка получения участников группы %d: %v", event.IDGroup, err)
+		return
+	}
+
+	for _, member := range members {
+		if member.IDChat == authorChatID || member.IDChat == 0 {
+			continue
+		}
+		msg := tgbotapi.NewMessage(member.IDChat, "💬 Новый комментарий к «"+
+			tgbotapi.EscapeText(tgbotapi.ModeMarkdown, event.NameEvent)+"»\n\n"+formatComment(comment, loadLocation(member.TimeZone)))
+		msg.ParseMode = "Markdown"
+		msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("💬 Ответить", fmt.Sprintf("comment_add_%d", event.IDEvent)),
+			tgbotapi.NewInlineKeyboardButtonData("Обсуждение", fmt.Sprintf("comment_%d", event.IDEvent)),
+		))
+		if _, err = bot.Send(msg); err != nil {
+			log.Printf("Ошибка отправки уведомления участнику %d: %v", member.IDChat, err)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"aliorToDoBot/src/db"
+	gorm_models2 "aliorToDoBot/src/db/gorm_models"
+)
+
+// ---- Функционал проверки пересечений мероприятий ----
+
+const (
+	conflictHorizon   = 90 * 24 * time.Hour // Насколько вперёд проверяются повторения нового мероприятия
+	maxConflictsShown = 10
+)
+
+// occurrenceBounds возвращает начало и конец повторения.
+// Мероприятие на весь день без продолжительности занимает весь день,
+// а мероприятие без продолжительности — одну минуту.
+func occurrenceBounds(event gorm_models2.Event) (time.Time, time.Time) {
+	start := event.DatetimeStart
+	switch {
+	case event.Duration > 0:
+		return start, start.Add(event.Duration)
+	case event.IsAllDay:
+		return start, start.In(eventLocation(event)).AddDate(0, 0, 1)
+	default:
+		return start, start.Add(time.Minute)
+	}
+}
+
+func occurrencesOverlap(a, b gorm_models2.Event) bool {
+	aStart, aEnd := occurrenceBounds(a)
+	bStart, bEnd := occurrenceBounds(b)
+	return aStart.Before(bEnd) && bStart.Before(aEnd)
+}
+
+// findConflicts возвращает ближайшие повторения мероприятий из групп пользователя,
+// пересекающиеся с новым мероприятием. Мероприятия из skip не проверяются.
+func findConflicts(ctx context.Context, event gorm_models2.Event, idUser int64, skip map[int64]bool) ([]occurrence, error) {
+	to := event.DatetimeStart.Add(conflictHorizon)
+	if event.RecurFreq == "" {
+		_, to = occurrenceBounds(event)
+	}
+	planned := eventOccurrences(event, nil, event.DatetimeStart, to)
+	if len(planned) == 0 {
+		return nil, nil
+	}
+	from, _ := occurrenceBounds(planned[0].Event)
+	_, to = occurrenceBounds(planned[len(planned)-1].Event)
+
+	events, err := provider.GetUserEventsInRange(ctx, idUser, from, to)
+	if err != nil {
+		return nil, err
+	}
+	exceptions := loadExceptions(events)
+
+	var result []occurrence
+	for _, other := range events {
+		if skip[other.IDEvent] {
+			continue
+		}
+		// Начало сдвинуто на сутки, чтобы не потерять мероприятия на весь день без продолжительности
+		for _, occ := range eventOccurrences(other, exceptions[other.IDEvent], from.AddDate(0, 0, -1), to) {
+			overlaps := false
+			for _, p := range planned {
+				if occurrencesOverlap(occ.Event, p.Event) {
+					overlaps = true
+					break
+				}
+			}
+			if overlaps {
+				result = append(result, occ)
+				break
+			}
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Event.DatetimeStart.Before(result[j].Event.DatetimeStart)
+	})
+	return result, nil
+}
+
+// checkEventConflicts ищет пересечения создаваемого мероприятия с мероприятиями пользователя
+// и других участников группы. Возвращает false, если пересечений нет.
+func checkEventConflicts(bot *tgbotapi.BotAPI, chatID int64, event gorm_models2.Event) bool {
+	ctx := context.Background()
+
+	members, err := provider.GetGroupMembers(ctx, event.IDGroup)
+	if err != nil {
+		log.Printf("Ошибка получения участников группы %d: %v", event.IDGroup, err)
+		return false
+	}
+
+	var (
+		own          []occurrence
+		others       []gorm_models2.User
+		seenConflict = make(map[int64]bool)
+	)
+	for _, member := range members {
+		if member.IDChat != chatID {
+			others = append(others, member)
+			continue
+		}
+		if own, err = findConflicts(ctx, event, member.IDUser, nil); err != nil {
+			log.Printf("Ошибка проверки пересечений для пользователя %d: %v", chatID, err)
+			return false
+		}
+	}
+	for _, occ := range own {
+		seenConflict[occ.Event.IDEvent] = true
+	}
+
+	// У других участников считаем только количество, не раскрывая их мероприятия
+	busyMembers, busyEvents := 0, 0
+	for _, member := range others {
+		conflicts, err := findConflicts(ctx, event, member.IDUser, seenConflict)
+		if err != nil {
+			log.Printf("Ошибка проверки пересечений для пользователя %d: %v", member.IDUser, err)
+			continue
+		}
+		if len(conflicts) > 0 {
+			busyMembers++
+			busyEvents += len(conflicts)
+		}
+	}
+
+	if len(own) == 0 && busyMembers == 0 {
+		return false
+	}
+
+	loc := userLocation(chatID)
+	var message strings.Builder
+	message.WriteString("⚠️ Мероприятие пересекается с другими делами.\n")
+	if len(own) > 0 {
+		groupIDs := make([]int64, 0, len(own))
+		for _, occ := range own {
+			groupIDs = append(groupIDs, occ.Event.IDGroup)
+		}
+		var groups []gorm_models2.Group
+		if err = db.DB.Where("id_group IN ?", groupIDs).Find(&groups).Error; err != nil {
+			log.Printf("Ошибка получения групп: %v", err)
+		}
+		groupNames := make(map[int64]string)
+		for _, group := range groups {
+			groupNames[group.IDGroup] = group.GroupName
+		}
+
+		message.WriteString("\nВаши мероприятия:\n")
+		for i, occ := range own {
+			if i == maxConflictsShown {
+				message.WriteString(fmt.Sprintf("…и ещё %d\n", len(own)-maxConflictsShown))
+				break
+			}
+			message.WriteString(fmt.Sprintf("• %s (%s) — %s\n",
+				occ.Event.NameEvent, groupNames[occ.Event.IDGroup], formatOccurrenceStart(occ.Event, loc)))
+		}
+	}
+	if busyMembers > 0 {
+		message.WriteString(fmt.Sprintf("\nУ других участников группы (%d) есть пересечения: мероприятий — %d.\n",
+			busyMembers, busyEvents))
+	}
+	message.WriteString("\nЧто сделать?")
+
+	userSteps[chatID] = "resolving_event_conflicts"
+	log.Printf("Переход к состоянию: %s", userSteps[chatID])
+	msg := tgbotapi.NewMessage(chatID, message.String())
+	msg.ReplyMarkup = tgbotapi.ReplyKeyboardMarkup{
+		Keyboard: [][]tgbotapi.KeyboardButton{
+			{tgbotapi.NewKeyboardButton("Сохранить всё равно")},
+			{tgbotapi.NewKeyboardButton("Выбрать другое время"), tgbotapi.NewKeyboardButton("Отмена")},
+		},
+		ResizeKeyboard: true,
+	}
+	if _, err = bot.Send(msg); err != nil {
+		log.Printf("Ошибка отправки сообщения: %v", err)
+	}
+	return true
+}
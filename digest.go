@@ -0,0 +1,280 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"aliorToDoBot/src/config"
+	"aliorToDoBot/src/dateparse"
+	gorm_models2 "aliorToDoBot/src/db/gorm_models"
+)
+
+// ---- Функционал ежедневной сводки ----
+
+// startDigestScheduler периодически отправляет ежедневные сводки, время которых наступило
+func startDigestScheduler(ctx context.Context, bot *tgbotapi.BotAPI, cfg *config.DigestConfig) {
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			sendDueDigests(ctx, bot)
+		}
+	}
+}
+
+// sendDueDigests отправляет сводку каждому пользователю, у которого наступило время отправки
+// и которому сводка за сегодня ещё не уходила. День и время считаются в часовом поясе пользователя.
+func sendDueDigests(ctx context.Context, bot *tgbotapi.BotAPI) {
+	settings, err := provider.GetEnabledDigests(ctx)
+	if err != nil {
+		log.Printf("Ошибка получения настроек ежедневных сводок: %v", err)
+		return
+	}
+
+	now := time.Now()
+	for _, setting := range settings {
+		local := now.In(loadLocation(setting.User.TimeZone))
+		day := local.Format("2006-01-02")
+		if setting.LastSentOn != nil && setting.LastSentOn.Format("2006-01-02") >= day {
+			continue
+		}
+		if local.Hour()*60+local.Minute() < setting.SendHour*60+setting.SendMinute {
+			continue
+		}
+
+		// Сводка отмечается до отправки, чтобы не уйти дважды, если отправка затянется до следующей проверки
+		claimed, err := provider.MarkDigestSent(ctx, setting.IDUser, day)
+		if err != nil {
+			log.Printf("Ошибка отметки сводки для пользователя %d: %v", setting.IDUser, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		text, hasContent, err := digestText(ctx, setting.User, now)
+		if err != nil {
+			log.Printf("Ошибка формирования сводки для пользователя %d: %v", setting.IDUser, err)
+			unmarkDigest(ctx, setting, day)
+			continue
+		}
+		if !hasContent && setting.SkipEmpty {
+			continue
+		}
+		msg := tgbotapi.NewMessage(setting.User.IDChat, text)
+		msg.ParseMode = "Markdown"
+		if _, err = bot.Send(msg); err != nil {
+			log.Printf("Ошибка отправки сводки пользователю %d: %v", setting.IDUser, err)
+			if !isPermanentSendError(err) {
+				unmarkDigest(ctx, setting, day)
+			}
+		}
+	}
+}
+
+// unmarkDigest снимает отметку о сводке за день, чтобы она ушла при следующей проверке
+func unmarkDigest(ctx context.Context, setting gorm_models2.DigestSetting, day string) {
+	if err := provider.UnmarkDigestSent(ctx, setting.IDUser, day, setting.LastSentOn); err != nil {
+		log.Printf("Ошибка снятия отметки сводки: %v", err)
+	}
+}
+
+// digestText формирует сводку пользователя на сегодня: мероприятия всех его групп и просроченные задачи.
+// Возвращает false, если сегодня нет ни мероприятий, ни просроченных задач.
+func digestText(ctx context.Context, user gorm_models2.User, now time.Time) (string, bool, error) {
+	loc := loadLocation(user.TimeZone)
+	from, to := agendaRange(agendaDay, 0, now.In(loc))
+
+	// Начало сдвинуто на сутки, чтобы не потерять мероприятия на весь день из других часовых поясов
+	events, err := provider.GetUserEventsInRange(ctx, user.IDUser, from.AddDate(0, 0, -1), to)
+	if err != nil {
+		return "", false, err
+	}
+	tasks, err := provider.GetOverdueTasks(ctx, user.IDChat, now)
+	if err != nil {
+		return "", false, err
+	}
+	groups, err := provider.GetUserGroups(ctx, user.IDChat)
+	if err != nil {
+		return "", false, err
+	}
+	groupMap := make(map[int64]string)
+	for _, group := range groups {
+		groupMap[group.IDGroup] = group.GroupName
+	}
+	_, byDay := dayOccurrences(events, loc, from, to)
+	occurrences := byDay[from]
+
+	var text strings.Builder
+	text.WriteString("☀️ *Сводка на " + dateparse.Describe(from, false) + "*\n\n")
+	if len(occurrences) == 0 && len(tasks) == 0 {
+		text.WriteString("Сегодня свободный день: мероприятий и просроченных задач нет.")
+		return text.String(), false, nil
+	}
+
+	var blocks []string
+	if len(occurrences) > 0 {
+		blocks = append(blocks, fmt.Sprintf("Мероприятия сегодня: %d\n\n", len(occurrences)))
+		for _, occ := range occurrences {
+			blocks = append(blocks, formatEvent(occ.Event, groupMap[occ.Event.IDGroup], loc)+"\n\n")
+		}
+	} else {
+		blocks = append(blocks, "Мероприятий сегодня нет.\n\n")
+	}
+	if len(tasks) > 0 {
+		blocks = append(blocks, fmt.Sprintf("⏰ Просроченные задачи: %d\n\n", len(tasks)))
+		for _, task := range tasks {
+			blocks = append(blocks, formatTask(task, groupMap[task.IDGroup], loc, now)+"\n\n")
+		}
+	}
+	for _, block := range blocks {
+		if !appendPageBlock(&text, block) {
+			break
+		}
+	}
+	return text.String(), true, nil
+}
+
+// digestSettingsView формирует описание и кнопки настройки ежедневной сводки
+func digestSettingsView(setting gorm_models2.DigestSetting) (string, tgbotapi.InlineKeyboardMarkup) {
+	sendAt := fmt.Sprintf("%02d:%02d", setting.SendHour, setting.SendMinute)
+
+	state, toggle := "выключена", "🔔 Включить"
+	if setting.Enabled {
+		state, toggle = "включена", "🔕 Выключить"
+	}
+	empty, emptyButton := "не присылать", "Пустые дни: не присылать"
+	if !setting.SkipEmpty {
+		empty, emptyButton = "присылать «свободный день»", "Пустые дни: присылать"
+	}
+
+	text := fmt.Sprintf("Ежедневная сводка: %s\n"+
+		"Время отправки: %s (%s)\n"+
+		"Дни без мероприятий и просроченных задач: %s\n\n"+
+		"В сводке — мероприятия всех ваших групп на сегодня и просроченные задачи.",
+		state, sendAt, loadLocation(setting.User.TimeZone).String(), empty)
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(toggle, "digest_toggle")),
+		tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData("🕗 Время: "+sendAt, "digest_time")),
+		tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(emptyButton, "digest_empty")),
+		tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData("👀 Прислать сейчас", "digest_now")),
+	)
+	return text, keyboard
+}
+
+// viewDigestSettings показывает настройку ежедневной сводки пользователя
+func viewDigestSettings(bot *tgbotapi.BotAPI, chatID int64) {
+	setting, err := provider.GetDigestSetting(context.Background(), chatID)
+	if err != nil {
+		log.Printf("Ошибка получения настроек сводки пользователя %d: %v", chatID, err)
+		bot.Send(tgbotapi.NewMessage(chatID, "Ошибка при получении настроек сводки."))
+		return
+	}
+
+	text, keyboard := digestSettingsView(setting)
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyMarkup = keyboard
+	if _, err = bot.Send(msg); err != nil {
+		log.Printf("Ошибка отправки сообщения: %v", err)
+	}
+}
+
+// handleDigestCallback обрабатывает кнопки настройки ежедневной сводки
+func handleDigestCallback(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+	ctx := context.Background()
+
+	setting, err := provider.GetDigestSetting(ctx, chatID)
+	if err != nil {
+		log.Printf("Ошибка получения настроек сводки пользователя %d: %v", chatID, err)
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Ошибка при получении настроек сводки."))
+		return
+	}
+
+	switch callback.Data {
+	case "digest_toggle":
+		setting.Enabled = !setting.Enabled
+	case "digest_empty":
+		setting.SkipEmpty = !setting.SkipEmpty
+
+	case "digest_time":
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		userSteps[chatID] = "setting_digest_time"
+		sendInputPrompt(bot, chatID, "Введите время отправки сводки в формате чч:мм, например 08:30:")
+		return
+
+	case "digest_now":
+		text, _, err := digestText(ctx, setting.User, time.Now())
+		if err != nil {
+			log.Printf("Ошибка формирования сводки для пользователя %d: %v", chatID, err)
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Не удалось сформировать сводку: "+err.Error()))
+			return
+		}
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		msg := tgbotapi.NewMessage(chatID, text)
+		msg.ParseMode = "Markdown"
+		if _, err = bot.Send(msg); err != nil {
+			log.Printf("Ошибка отправки сводки пользователю %d: %v", chatID, err)
+		}
+		return
+
+	default:
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Неизвестное действие"))
+		return
+	}
+
+	if err = provider.SaveDigestSetting(ctx, chatID, setting); err != nil {
+		log.Printf("Ошибка сохранения настроек сводки пользователя %d: %v", chatID, err)
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Ошибка при сохранении настроек сводки."))
+		return
+	}
+	bot.Request(tgbotapi.NewCallback(callback.ID, "Настройки сводки сохранены."))
+
+	text, keyboard := digestSettingsView(setting)
+	edit := tgbotapi.NewEditMessageTextAndMarkup(chatID, callback.Message.MessageID, text, keyboard)
+	if _, err = bot.Send(edit); err != nil {
+		log.Printf("Ошибка обновления настроек сводки: %v", err)
+	}
+}
+
+// handleDigestTimeInput сохраняет введённое время отправки сводки
+func handleDigestTimeInput(bot *tgbotapi.BotAPI, chatID int64, text string) {
+	if text == "Главное меню" {
+		delete(userSteps, chatID)
+		sendMainMenu(bot, chatID)
+		return
+	}
+
+	sendAt, err := time.Parse("15:04", strings.TrimSpace(text))
+	if err != nil {
+		bot.Send(tgbotapi.NewMessage(chatID, "Неверный формат времени. Введите время в формате чч:мм, например 08:30."))
+		return
+	}
+
+	ctx := context.Background()
+	setting, err := provider.GetDigestSetting(ctx, chatID)
+	if err != nil {
+		log.Printf("Ошибка получения настроек сводки пользователя %d: %v", chatID, err)
+		bot.Send(tgbotapi.NewMessage(chatID, "Ошибка при получении настроек сводки."))
+		return
+	}
+	setting.SendHour, setting.SendMinute = sendAt.Hour(), sendAt.Minute()
+	if err = provider.SaveDigestSetting(ctx, chatID, setting); err != nil {
+		log.Printf("Ошибка сохранения настроек сводки пользователя %d: %v", chatID, err)
+		bot.Send(tgbotapi.NewMessage(chatID, "Ошибка при сохранении настроек сводки."))
+		return
+	}
+
+	delete(userSteps, chatID)
+	bot.Send(tgbotapi.NewMessage(chatID, "Время отправки сводки сохранено."))
+	sendSettingsMenu(bot, chatID)
+	viewDigestSettings(bot, chatID)
+}
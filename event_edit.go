@@ -0,0 +1,362 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"aliorToDoBot/src/dateparse"
+	"aliorToDoBot/src/db"
+	gorm_models2 "aliorToDoBot/src/db/gorm_models"
+)
+
+// ---- Функционал редактирования мероприятий ----
+
+var pendingEditEvent = make(map[int64]gorm_models2.Event) // Мероприятие с новым временем, ожидающим подтверждения
+
+// startEditEvent загружает мероприятие во временное хранилище и показывает меню редактирования.
+func startEditEvent(bot *tgbotapi.BotAPI, chatID int64, eventID int64) {
+	var event gorm_models2.Event
+	if err := db.DB.First(&event, eventID).Error; err != nil {
+		log.Printf("Ошибка получения мероприятия: %v", err)
+		bot.Send(tgbotapi.NewMessage(chatID, "Мероприятие не найдено."))
+		return
+	}
+
+	isAdmin, err := provider.IsGroupAdmin(context.Background(), chatID, event.IDGroup)
+	if err != nil {
+		log.Printf("Ошибка проверки прав пользователя %d: %v", chatID, err)
+		bot.Send(tgbotapi.NewMessage(chatID, "Произошла ошибка. Попробуйте позже."))
+		return
+	}
+	if !isAdmin {
+		bot.Send(tgbotapi.NewMessage(chatID, "Только администратор группы может редактировать мероприятие."))
+		return
+	}
+
+	editEvent[chatID] = event
+	delete(userSteps, chatID)
+	sendEditEventMenu(bot, chatID)
+}
+
+// sendEditEventMenu показывает текущее состояние редактируемого мероприятия и список полей.
+func sendEditEventMenu(bot *tgbotapi.BotAPI, chatID int64) {
+	event, ok := editEvent[chatID]
+	if !ok {
+		bot.Send(tgbotapi.NewMessage(chatID, "Нет мероприятия для редактирования."))
+		return
+	}
+
+	var group gorm_models2.Group
+	if err := db.DB.First(&group, event.IDGroup).Error; err != nil {
+		log.Printf("Ошибка получения группы с ID %d: %v", event.IDGroup, err)
+	}
+
+	allDayLabel := "Весь день: нет"
+	if event.IsAllDay {
+		allDayLabel = "Весь день: да"
+	}
+
+	msg := tgbotapi.NewMessage(chatID, "Редактирование мероприятия:\n\n"+formatEvent(event, group.GroupName, userLocation(chatID))+
+		"\n\nЧтобы прикрепить документ или фото, отправьте его в чат.")
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("Название", "edit_field_name"),
+			tgbotapi.NewInlineKeyboardButtonData("Категория", "edit_field_category"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("Время начала", "edit_field_time"),
+			tgbotapi.NewInlineKeyboardButtonData(allDayLabel, "edit_field_allday"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("Продолжительность", "edit_field_duration"),
+			tgbotapi.NewInlineKeyboardButtonData("Группа", "edit_field_group"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("Ссылка на встречу", "edit_field_link"),
+			tgbotapi.NewInlineKeyboardButtonData("Место", "edit_field_location"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("💾 Сохранить", "edit_save"),
+			tgbotapi.NewInlineKeyboardButtonData("Отмена", "edit_cancel"),
+		),
+	)
+	if _, err := bot.Send(msg); err != nil {
+		log.Printf("Ошибка отправки сообщения: %v", err)
+	}
+}
+
+// handleEditCallback обрабатывает инлайн-кнопки меню редактирования.
+func handleEditCallback(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+	data := callback.Data
+
+	event, ok := editEvent[chatID]
+	if !ok {
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Редактирование уже завершено."))
+		return
+	}
+	bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+
+	switch {
+	case data == "edit_field_name":
+		userSteps[chatID] = "editing_event_name"
+		bot.Send(tgbotapi.NewMessage(chatID, "Введите новое название мероприятия:"))
+
+	case data == "edit_field_category":
+		userSteps[chatID] = "editing_event_category"
+		sendCategoryPrompt(bot, chatID, event.IDGroup, "Выберите новую категорию:")
+
+	case data == "edit_field_time":
+		if event.IsAllDay {
+			userSteps[chatID] = "editing_event_all_day_date"
+			bot.Send(tgbotapi.NewMessage(chatID, "Введите новую дату, например «25 декабря», «в субботу» или дд.мм.гггг:"))
+			sendDatePicker(bot, chatID)
+			return
+		}
+		userSteps[chatID] = "editing_event_time"
+		bot.Send(tgbotapi.NewMessage(chatID, "Введите новые дату и время начала, например «завтра в 15:00» или дд.мм.гггг чч:мм:"))
+		sendDatePicker(bot, chatID)
+
+	case data == "edit_field_allday":
+		event.IsAllDay = !event.IsAllDay
+		if event.IsAllDay {
+			// Для мероприятия на весь день время не имеет значения
+			loc := eventLocation(event)
+			year, month, day := event.DatetimeStart.In(loc).Date()
+			event.DatetimeStart = time.Date(year, month, day, 0, 0, 0, 0, loc)
+			editEvent[chatID] = event
+			sendEditEventMenu(bot, chatID)
+			return
+		}
+		editEvent[chatID] = event
+		userSteps[chatID] = "editing_event_time"
+		bot.Send(tgbotapi.NewMessage(chatID, "Введите дату и время начала, например «завтра в 15:00» или дд.мм.гггг чч:мм:"))
+		sendDatePicker(bot, chatID)
+
+	case data == "edit_field_duration":
+		userSteps[chatID] = "editing_event_duration"
+		bot.Send(tgbotapi.NewMessage(chatID, "Введите новую продолжительность (например, 1d2h) или 0, чтобы убрать её:"))
+
+	case data == "edit_field_link":
+		userSteps[chatID] = "editing_event_link"
+		text := "Введите ссылку на видеовстречу:"
+		if event.LinkToVideo != "" {
+			text = "Текущая ссылка: " + event.LinkToVideo + "\nВведите новую ссылку или «-», чтобы убрать её:"
+		}
+		bot.Send(tgbotapi.NewMessage(chatID, text))
+
+	case data == "edit_field_location":
+		userSteps[chatID] = "editing_event_location"
+		text := "Отправьте геопозицию или точку на карте через 📎 либо введите адрес текстом:"
+		if !event.EventLocation.IsEmpty() {
+			text = "Текущее место: " + formatLocation(event.EventLocation) + "\n" + text + " Чтобы убрать место, введите «-»."
+		}
+		msg := tgbotapi.NewMessage(chatID, text)
+		msg.ReplyMarkup = locationKeyboard("-")
+		bot.Send(msg)
+
+	case data == "edit_field_group":
+		groups, err := provider.GetAdminGroups(context.Background(), chatID)
+		if err != nil {
+			log.Printf("Ошибка получения групп пользователя %d: %v", chatID, err)
+			bot.Send(tgbotapi.NewMessage(chatID, "Ошибка при получении ваших групп."))
+			return
+		}
+		var inlineKeyboard [][]tgbotapi.InlineKeyboardButton
+		for _, group := range groups {
+			button := tgbotapi.NewInlineKeyboardButtonData(group.GroupName, fmt.Sprintf("edit_group_%d", group.IDGroup))
+			inlineKeyboard = append(inlineKeyboard, tgbotapi.NewInlineKeyboardRow(button))
+		}
+		msg := tgbotapi.NewMessage(chatID, "Выберите новую группу:")
+		msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(inlineKeyboard...)
+		bot.Send(msg)
+
+	case strings.HasPrefix(data, "edit_group_"):
+		groupID, err := strconv.ParseInt(strings.TrimPrefix(data, "edit_group_"), 10, 64)
+		if err != nil {
+			log.Printf("Ошибка обработки ID группы: %v", err)
+			return
+		}
+		event.IDGroup = groupID
+		editEvent[chatID] = event
+		sendEditEventMenu(bot, chatID)
+
+	case data == "edit_save":
+		err := provider.UpdateEvent(context.Background(), chatID, event.IDEvent, event.IDGroup,
+			event.NameEvent, event.Category, event.IsAllDay, event.DatetimeStart, event.TimeZone, event.Duration, event.LinkToVideo,
+			event.EventLocation)
+		if err != nil {
+			log.Printf("Ошибка обновления мероприятия ID %d: %v", event.IDEvent, err)
+			bot.Send(tgbotapi.NewMessage(chatID, "Не удалось сохранить изменения: "+err.Error()))
+			return
+		}
+		delete(editEvent, chatID)
+		delete(userSteps, chatID)
+		bot.Send(tgbotapi.NewMessage(chatID, "Мероприятие успешно обновлено!"))
+		UpdateEventStatuses(db.DB)
+		viewMyEvents(bot, chatID)
+
+	case data == "edit_cancel":
+		delete(editEvent, chatID)
+		delete(userSteps, chatID)
+		bot.Send(tgbotapi.NewMessage(chatID, "Редактирование отменено."))
+		viewMyEvents(bot, chatID)
+	}
+}
+
+// handleEventEditing обрабатывает ввод новых значений полей мероприятия.
+func handleEventEditing(bot *tgbotapi.BotAPI, chatID int64, text string) {
+	if text == "Главное меню" {
+		delete(editEvent, chatID)
+		delete(pendingEditEvent, chatID)
+		delete(userSteps, chatID)
+		sendMainMenu(bot, chatID)
+		return
+	}
+
+	event, ok := editEvent[chatID]
+	if !ok {
+		delete(userSteps, chatID)
+		return
+	}
+
+	switch userSteps[chatID] {
+	case "editing_event_name":
+		if strings.TrimSpace(text) == "" {
+			bot.Send(tgbotapi.NewMessage(chatID, "Название не может быть пустым."))
+			return
+		}
+		event.NameEvent = text
+
+	case "editing_event_category":
+		categories, err := provider.GetGroupCategories(context.Background(), event.IDGroup)
+		if err != nil {
+			log.Printf("Ошибка получения категорий группы %d: %v", event.IDGroup, err)
+			bot.Send(tgbotapi.NewMessage(chatID, "Ошибка при получении категорий группы."))
+			return
+		}
+		category, ok := matchCategory(categories, text)
+		if !ok {
+			bot.Send(tgbotapi.NewMessage(chatID, "Некорректная категория. Пожалуйста, выберите одну из кнопок."))
+			return
+		}
+		event.Category = category.Name
+
+	case "editing_event_time":
+		loc := userLocation(chatID)
+		startTime, hasTime, err := dateparse.Parse(text, time.Now().In(loc))
+		if err != nil {
+			log.Printf("Ошибка парсинга даты '%s': %v", text, err)
+			bot.Send(tgbotapi.NewMessage(chatID, "Не удалось распознать дату и время. Попробуйте, например, «завтра в 15:00», "+
+				"«в пятницу 10:30» или дд.мм.гггг чч:мм."))
+			return
+		}
+		if !hasTime {
+			bot.Send(tgbotapi.NewMessage(chatID, "Укажите также время начала, например «"+text+" в 15:00»."))
+			return
+		}
+		event.DatetimeStart = startTime
+		event.TimeZone = loc.String()
+		event.IsAllDay = false
+		pendingEditEvent[chatID] = event
+		confirmParsedTime(bot, chatID, "confirming_edit_time", dateparse.Describe(startTime, true))
+		return
+
+	case "editing_event_all_day_date":
+		loc := userLocation(chatID)
+		allDayDate, _, err := dateparse.Parse(text, time.Now().In(loc))
+		if err != nil {
+			log.Printf("Ошибка парсинга даты '%s': %v", text, err)
+			bot.Send(tgbotapi.NewMessage(chatID, "Не удалось распознать дату. Попробуйте, например, «25 декабря», «в субботу» или дд.мм.гггг."))
+			return
+		}
+		// Для мероприятия на весь день время не учитывается
+		allDayDate = time.Date(allDayDate.Year(), allDayDate.Month(), allDayDate.Day(), 0, 0, 0, 0, loc)
+		event.DatetimeStart = allDayDate
+		event.TimeZone = loc.String()
+		event.IsAllDay = true
+		pendingEditEvent[chatID] = event
+		confirmParsedTime(bot, chatID, "confirming_edit_time", dateparse.Describe(allDayDate, false)+", весь день")
+		return
+
+	case "confirming_edit_time":
+		pending, ok := pendingEditEvent[chatID]
+		if !ok {
+			delete(userSteps, chatID)
+			sendEditEventMenu(bot, chatID)
+			return
+		}
+		switch text {
+		case "Да":
+			delete(pendingEditEvent, chatID)
+			event = pending
+			if first, ok := firstOccurrenceStart(event); ok && event.RecurFreq != "" && !first.Equal(event.DatetimeStart) {
+				event.DatetimeStart = first
+				bot.Send(tgbotapi.NewMessage(chatID, "Первое повторение по правилу — "+
+					dateparse.Describe(first, !event.IsAllDay)+". Мероприятие начнётся с этой даты."))
+			}
+		case "Нет":
+			delete(pendingEditEvent, chatID)
+			if pending.IsAllDay {
+				userSteps[chatID] = "editing_event_all_day_date"
+				bot.Send(tgbotapi.NewMessage(chatID, "Введите дату ещё раз, например «25 декабря» или «в субботу»:"))
+			} else {
+				userSteps[chatID] = "editing_event_time"
+				bot.Send(tgbotapi.NewMessage(chatID, "Введите дату и время начала ещё раз, например «завтра в 15:00»:"))
+			}
+			sendDatePicker(bot, chatID)
+			return
+		default:
+			bot.Send(tgbotapi.NewMessage(chatID, "Пожалуйста, ответьте 'Да' или 'Нет'."))
+			return
+		}
+
+	case "editing_event_link":
+		if strings.TrimSpace(text) == "-" {
+			event.LinkToVideo = ""
+			break
+		}
+		link, err := parseMeetingLink(text)
+		if err != nil {
+			bot.Send(tgbotapi.NewMessage(chatID, "Некорректная ссылка: "+err.Error()+". Пример: https://meet.google.com/abc-defg-hij"))
+			return
+		}
+		event.LinkToVideo = link
+
+	case "editing_event_location":
+		if strings.TrimSpace(text) == "-" {
+			event.EventLocation = gorm_models2.EventLocation{}
+			break
+		}
+		location, err := parseLocationText(text)
+		if err != nil {
+			bot.Send(tgbotapi.NewMessage(chatID, "Некорректный адрес: "+err.Error()))
+			return
+		}
+		event.EventLocation = location
+
+	case "editing_event_duration":
+		if text == "0" {
+			event.Duration = 0
+			break
+		}
+		duration, err := parseDuration(text)
+		if err != nil {
+			log.Printf("Ошибка парсинга продолжительности: %v", err)
+			bot.Send(tgbotapi.NewMessage(chatID, "Неверный формат продолжительности. Используйте формат 1d2h3m. Пример: 1d2h или 2h30m."))
+			return
+		}
+		event.Duration = duration
+	}
+
+	editEvent[chatID] = event
+	delete(userSteps, chatID)
+	sendEditEventMenu(bot, chatID)
+}
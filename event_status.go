@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"aliorToDoBot/src/dateparse"
+	"aliorToDoBot/src/db"
+	gorm_models2 "aliorToDoBot/src/db/gorm_models"
+)
+
+// ---- Функционал ручной смены статуса мероприятия ----
+
+var postponeEvent = make(map[int64]int64) // ID мероприятия, которое переносит администратор
+
+// eventStatusRow возвращает кнопки ручной смены статуса для карточки мероприятия.
+// Если статус уже выставлен вручную, его можно только вернуть к автоматическому.
+func eventStatusRow(event gorm_models2.Event) []tgbotapi.InlineKeyboardButton {
+	if event.StatusManual {
+		return tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("↩️ Вернуть статус", fmt.Sprintf("evst_restore_%d", event.IDEvent)),
+		)
+	}
+
+	row := tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("🚫 Отменить", fmt.Sprintf("evst_cancel_%d", event.IDEvent)),
+	)
+	// Повторяющиеся мероприятия переносятся по одному повторению
+	if event.RecurFreq == "" {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData("⏩ Перенести", fmt.Sprintf("evst_postpone_%d", event.IDEvent)))
+	}
+	if event.Status != gorm_models2.EventStatusFinished {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData("🏁 Завершить", fmt.Sprintf("evst_finish_%d", event.IDEvent)))
+	}
+	return row
+}
+
+// handleEventStatusCallback обрабатывает кнопки смены статуса в карточке мероприятия
+func handleEventStatusCallback(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+	data := callback.Data
+	ctx := context.Background()
+
+	var prefix string
+	for _, p := range []string{"evst_cancel_", "evst_postpone_", "evst_finish_", "evst_restore_"} {
+		if strings.HasPrefix(data, p) {
+			prefix = p
+			break
+		}
+	}
+	eventID, err := strconv.ParseInt(strings.TrimPrefix(data, prefix), 10, 64)
+	if prefix == "" || err != nil {
+		log.Printf("Некорректные данные кнопки статуса мероприятия: %s", data)
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Неизвестное действие"))
+		return
+	}
+
+	switch prefix {
+	case "evst_cancel_":
+		if err = provider.CancelEvent(ctx, chatID, eventID); err != nil {
+			log.Printf("Ошибка отмены мероприятия ID %d: %v", eventID, err)
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Не удалось отменить мероприятие: "+err.Error()))
+			return
+		}
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Мероприятие отменено."))
+		notifyEventStatus(bot, chatID, eventID, "🚫 Мероприятие отменено:")
+
+	case "evst_finish_":
+		if err = provider.FinishEvent(ctx, chatID, eventID); err != nil {
+			log.Printf("Ошибка завершения мероприятия ID %d: %v", eventID, err)
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Не удалось завершить мероприятие: "+err.Error()))
+			return
+		}
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Мероприятие завершено."))
+
+	case "evst_restore_":
+		if err = provider.RestoreEventStatus(ctx, chatID, eventID); err != nil {
+			log.Printf("Ошибка восстановления статуса мероприятия ID %d: %v", eventID, err)
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Не удалось вернуть статус: "+err.Error()))
+			return
+		}
+		UpdateEventStatuses(db.DB)
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Статус снова вычисляется автоматически."))
+
+	case "evst_postpone_":
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		postponeEvent[chatID] = eventID
+		userSteps[chatID] = "postponing_event"
+		msg := tgbotapi.NewMessage(chatID, "Введите новые дату и время, например «завтра в 15:00» или дд.мм.гггг чч:мм:")
+		msg.ReplyMarkup = tgbotapi.ReplyKeyboardMarkup{
+			Keyboard: [][]tgbotapi.KeyboardButton{
+				{tgbotapi.NewKeyboardButton("Главное меню")},
+			},
+			ResizeKeyboard: true,
+		}
+		bot.Send(msg)
+		return
+	}
+
+	refreshEventCard(bot, callback.Message, eventID)
+}
+
+// handlePostponeInput переносит мероприятие на введённое время
+func handlePostponeInput(bot *tgbotapi.BotAPI, chatID int64, text string) {
+	eventID, ok := postponeEvent[chatID]
+	if !ok || text == "Главное меню" {
+		delete(postponeEvent, chatID)
+		delete(userSteps, chatID)
+		sendMainMenu(bot, chatID)
+		return
+	}
+
+	var event gorm_models2.Event
+	if err := db.DB.First(&event, eventID).Error; err != nil {
+		log.Printf("Ошибка получения мероприятия: %v", err)
+		delete(postponeEvent, chatID)
+		delete(userSteps, chatID)
+		bot.Send(tgbotapi.NewMessage(chatID, "Мероприятие не найдено."))
+		return
+	}
+
+	// Мероприятие на весь день переносится в часовом поясе мероприятия, остальные — в поясе пользователя
+	loc := userLocation(chatID)
+	if event.IsAllDay {
+		loc = eventLocation(event)
+	}
+	newStart, hasTime, err := dateparse.Parse(text, time.Now().In(loc))
+	if err != nil {
+		bot.Send(tgbotapi.NewMessage(chatID, "Не удалось распознать дату и время. Попробуйте, например, «завтра в 15:00» или дд.мм.гггг чч:мм."))
+		return
+	}
+	if event.IsAllDay {
+		year, month, day := newStart.Date()
+		newStart = time.Date(year, month, day, 0, 0, 0, 0, loc)
+	} else if !hasTime {
+		bot.Send(tgbotapi.NewMessage(chatID, "Укажите также время начала, например «"+text+" в 15:00»."))
+		return
+	}
+
+	if err = provider.PostponeEvent(context.Background(), chatID, eventID, newStart); err != nil {
+		log.Printf("Ошибка переноса мероприятия ID %d: %v", eventID, err)
+		bot.Send(tgbotapi.NewMessage(chatID, "Не удалось перенести мероприятие: "+err.Error()))
+		return
+	}
+
+	delete(postponeEvent, chatID)
+	delete(userSteps, chatID)
+	bot.Send(tgbotapi.NewMessage(chatID, "Мероприятие перенесено на "+dateparse.Describe(newStart, !event.IsAllDay)+"."))
+	notifyEventStatus(bot, chatID, eventID, "⏩ Мероприятие перенесено:")
+	sendEventsMenu(bot, chatID)
+	viewEventCard(bot, chatID, eventID)
+}
+
+// notifyEventStatus сообщает участникам группы, кроме автора изменения, о смене статуса мероприятия
+func notifyEventStatus(bot *tgbotapi.BotAPI, actorChatID int64, eventID int64, header string) {
+	var event gorm_models2.Event
+	if err := db.DB.First(&event, eventID).Error; err != nil {
+		log.Printf("Ошибка получения мероприятия ID %d: %v", eventID, err)
+		return
+	}
+	members, err := provider.GetGroupMembers(context.Background(), event.IDGroup)
+	if err != nil {
+		log.Printf("Ошибка получения участников группы %d: %v", event.IDGroup, err)
+		return
+	}
+	var group gorm_models2.Group
+	if err = db.DB.First(&group, event.IDGroup).Error; err != nil {
+		log.Printf("Ошибка получения группы с ID %d: %v", event.IDGroup, err)
+	}
+
+	for _, member := range members {
+		if member.IDChat == actorChatID || member.IDChat == 0 {
+			continue
+		}
+		msg := tgbotapi.NewMessage(member.IDChat, header+"\n\n"+formatEvent(event, group.GroupName, loadLocation(member.TimeZone)))
+		msg.ParseMode = "Markdown"
+		if _, err = bot.Send(msg); err != nil {
+			log.Printf("Ошибка отправки уведомления участнику %d: %v", member.IDChat, err)
+		}
+	}
+}
@@ -0,0 +1,415 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"aliorToDoBot/src/db"
+	gorm_models2 "aliorToDoBot/src/db/gorm_models"
+)
+
+// ---- Функционал доски задач ----
+
+const boardDoneLimit = 5 // Сколько задач показывать в колонках выполненных, чтобы доска оставалась компактной
+
+var taskColumnTarget = make(map[int64]int64) // ID группы для новой колонки или ID переименовываемой колонки
+
+// taskBoard формирует доску задач группы: колонки с карточками и кнопки с номерами карточек.
+// Нажатие на номер открывает выбор колонки для переноса.
+func taskBoard(chatID int64, idGroup int64) (string, tgbotapi.InlineKeyboardMarkup, error) {
+	ctx := context.Background()
+
+	tasks, err := provider.GetGroupTasks(ctx, chatID, idGroup)
+	if err != nil {
+		return "", tgbotapi.InlineKeyboardMarkup{}, err
+	}
+	columns, err := provider.GetTaskColumns(ctx, idGroup)
+	if err != nil {
+		return "", tgbotapi.InlineKeyboardMarkup{}, err
+	}
+	if len(columns) == 0 {
+		return "", tgbotapi.InlineKeyboardMarkup{}, fmt.Errorf("у группы нет колонок доски")
+	}
+
+	byColumn := make(map[int64][]gorm_models2.Task)
+	for _, task := range tasks {
+		idColumn := taskColumnID(task, columns)
+		byColumn[idColumn] = append(byColumn[idColumn], task)
+	}
+
+	var group gorm_models2.Group
+	if err = db.DB.First(&group, idGroup).Error; err != nil {
+		log.Printf("Ошибка получения группы с ID %d: %v", idGroup, err)
+	}
+
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("📋 Доска «%s»\n", tgbotapi.EscapeText(tgbotapi.ModeMarkdown, group.GroupName)))
+
+	// Доска с большим числом задач может не поместиться в сообщение: лишние карточки отбрасываются с пометкой
+	var numbers []tgbotapi.InlineKeyboardButton
+columns:
+	for _, column := range columns {
+		cards := byColumn[column.IDColumn]
+		if !appendPageBlock(&text, fmt.Sprintf("\n*%s* (%d)\n", tgbotapi.EscapeText(tgbotapi.ModeMarkdown, column.Name), len(cards))) {
+			break
+		}
+		if len(cards) == 0 {
+			if !appendPageBlock(&text, "—\n") {
+				break
+			}
+			continue
+		}
+
+		hidden := 0
+		if column.IsDone && len(cards) > boardDoneLimit {
+			hidden = len(cards) - boardDoneLimit
+			cards = cards[:boardDoneLimit]
+		}
+		for _, task := range cards {
+			n := len(numbers) + 1
+			line := fmt.Sprintf("%d. %s %s", n, taskPriorityIcons[task.Priority],
+				tgbotapi.EscapeText(tgbotapi.ModeMarkdown, task.Title))
+			if len(task.Assignees) > 0 {
+				names := make([]string, 0, len(task.Assignees))
+				for _, assignee := range task.Assignees {
+					names = append(names, tgbotapi.EscapeText(tgbotapi.ModeMarkdown, assignee.User.UserName))
+				}
+				line += " — " + strings.Join(names, ", ")
+			}
+			if !appendPageBlock(&text, line+"\n") {
+				break columns
+			}
+			numbers = append(numbers, tgbotapi.NewInlineKeyboardButtonData(strconv.Itoa(n),
+				fmt.Sprintf("board_sel_%d", task.IDTask)))
+		}
+		if hidden > 0 && !appendPageBlock(&text, fmt.Sprintf("…и ещё %d\n", hidden)) {
+			break
+		}
+	}
+
+	var inlineKeyboard [][]tgbotapi.InlineKeyboardButton
+	for len(numbers) > 0 {
+		size := min(len(numbers), 5)
+		inlineKeyboard = append(inlineKeyboard, numbers[:size])
+		numbers = numbers[size:]
+	}
+	isAdmin, err := provider.IsGroupAdmin(ctx, chatID, idGroup)
+	if err != nil {
+		log.Printf("Ошибка проверки прав пользователя %d: %v", chatID, err)
+	}
+	if isAdmin {
+		inlineKeyboard = append(inlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("⚙️ Колонки", fmt.Sprintf("board_cols_%d", idGroup)),
+		))
+	}
+	const boardHint = "\nНажмите на номер задачи, чтобы перенести её в другую колонку."
+	if len(inlineKeyboard) > 0 && messageLength(text.String())+messageLength(boardHint) <= maxMessageLength {
+		text.WriteString(boardHint)
+	}
+	return text.String(), tgbotapi.NewInlineKeyboardMarkup(inlineKeyboard...), nil
+}
+
+// taskColumnID возвращает колонку, в которой показывается задача.
+// Задача без колонки попадает в первую колонку с тем же признаком выполнения.
+func taskColumnID(task gorm_models2.Task, columns []gorm_models2.TaskColumn) int64 {
+	if task.IDColumn != nil {
+		return *task.IDColumn
+	}
+	for _, column := range columns {
+		if column.IsDone == task.IsDone {
+			return column.IDColumn
+		}
+	}
+	if len(columns) > 0 {
+		return columns[0].IDColumn
+	}
+	return 0
+}
+
+// taskMoveKeyboard возвращает кнопки переноса задачи во все остальные колонки доски
+func taskMoveKeyboard(task gorm_models2.Task, columns []gorm_models2.TaskColumn) tgbotapi.InlineKeyboardMarkup {
+	var inlineKeyboard [][]tgbotapi.InlineKeyboardButton
+	current := taskColumnID(task, columns)
+	for _, column := range columns {
+		if column.IDColumn == current {
+			continue
+		}
+		inlineKeyboard = append(inlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("→ "+column.Name, fmt.Sprintf("board_mv_%d_%d", task.IDTask, column.IDColumn)),
+		))
+	}
+	inlineKeyboard = append(inlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("« Назад", fmt.Sprintf("board_back_%d", task.IDGroup)),
+	))
+	return tgbotapi.NewInlineKeyboardMarkup(inlineKeyboard...)
+}
+
+// viewBoardGroups предлагает выбрать группу, доску которой показать
+func viewBoardGroups(bot *tgbotapi.BotAPI, chatID int64) {
+	groups, err := provider.GetUserGroups(context.Background(), chatID)
+	if err != nil {
+		log.Printf("Ошибка получения групп пользователя %d: %v", chatID, err)
+		bot.Send(tgbotapi.NewMessage(chatID, "Ошибка при получении ваших групп."))
+		return
+	}
+	if len(groups) == 0 {
+		bot.Send(tgbotapi.NewMessage(chatID, "Вы пока не состоите ни в одной группе."))
+		return
+	}
+	if len(groups) == 1 {
+		viewTaskBoard(bot, chatID, groups[0].IDGroup)
+		return
+	}
+
+	var inlineKeyboard [][]tgbotapi.InlineKeyboardButton
+	for _, group := range groups {
+		inlineKeyboard = append(inlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(group.GroupName, fmt.Sprintf("board_%d", group.IDGroup)),
+		))
+	}
+	msg := tgbotapi.NewMessage(chatID, "Выберите группу, доску которой показать:")
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(inlineKeyboard...)
+	bot.Send(msg)
+}
+
+// viewTaskBoard отправляет доску задач группы
+func viewTaskBoard(bot *tgbotapi.BotAPI, chatID int64, idGroup int64) {
+	text, keyboard, err := taskBoard(chatID, idGroup)
+	if err != nil {
+		log.Printf("Ошибка получения доски группы %d: %v", idGroup, err)
+		bot.Send(tgbotapi.NewMessage(chatID, "Не удалось открыть доску: "+err.Error()))
+		return
+	}
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = keyboard
+	if _, err = bot.Send(msg); err != nil {
+		log.Printf("Ошибка отправки сообщения: %v", err)
+	}
+}
+
+// refreshTaskBoard перерисовывает доску задач в том же сообщении
+func refreshTaskBoard(bot *tgbotapi.BotAPI, message *tgbotapi.Message, idGroup int64) {
+	text, keyboard, err := taskBoard(message.Chat.ID, idGroup)
+	if err != nil {
+		log.Printf("Ошибка получения доски группы %d: %v", idGroup, err)
+		return
+	}
+	edit := tgbotapi.NewEditMessageTextAndMarkup(message.Chat.ID, message.MessageID, text, keyboard)
+	edit.ParseMode = "Markdown"
+	if _, err = bot.Send(edit); err != nil {
+		log.Printf("Ошибка обновления доски: %v", err)
+	}
+}
+
+// handleBoardCallback обрабатывает кнопки доски задач
+func handleBoardCallback(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+	data := callback.Data
+	ctx := context.Background()
+
+	if strings.HasPrefix(data, "board_mv_") {
+		// Данные вида board_mv_<IDTask>_<IDColumn>
+		taskPart, columnPart, _ := strings.Cut(strings.TrimPrefix(data, "board_mv_"), "_")
+		taskID, err := strconv.ParseInt(taskPart, 10, 64)
+		if err != nil {
+			log.Printf("Некорректные данные кнопки доски: %s", data)
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Неизвестное действие"))
+			return
+		}
+		columnID, err := strconv.ParseInt(columnPart, 10, 64)
+		if err != nil {
+			log.Printf("Некорректные данные кнопки доски: %s", data)
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Неизвестное действие"))
+			return
+		}
+
+		if err = provider.MoveTask(ctx, chatID, taskID, columnID); err != nil {
+			log.Printf("Ошибка переноса задачи ID %d: %v", taskID, err)
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Не удалось перенести задачу: "+err.Error()))
+			return
+		}
+		task, err := provider.GetTask(ctx, taskID)
+		if err != nil {
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Задача не найдена."))
+			return
+		}
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Задача перенесена."))
+		refreshTaskBoard(bot, callback.Message, task.IDGroup)
+		return
+	}
+
+	var prefix string
+	for _, p := range []string{"board_sel_", "board_back_", "board_cols_", "board_"} {
+		if strings.HasPrefix(data, p) {
+			prefix = p
+			break
+		}
+	}
+	id, err := strconv.ParseInt(strings.TrimPrefix(data, prefix), 10, 64)
+	if err != nil {
+		log.Printf("Некорректные данные кнопки доски: %s", data)
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Неизвестное действие"))
+		return
+	}
+
+	switch prefix {
+	case "board_":
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		viewTaskBoard(bot, chatID, id)
+
+	case "board_back_":
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		refreshTaskBoard(bot, callback.Message, id)
+
+	case "board_sel_":
+		task, err := provider.GetTask(ctx, id)
+		if err != nil {
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Задача не найдена."))
+			return
+		}
+		if err = provider.CheckGroupMember(ctx, chatID, task.IDGroup); err != nil {
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Задача не найдена."))
+			return
+		}
+		columns, err := provider.GetTaskColumns(ctx, task.IDGroup)
+		if err != nil {
+			log.Printf("Ошибка получения колонок группы %d: %v", task.IDGroup, err)
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Не удалось получить колонки доски."))
+			return
+		}
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Куда перенести «"+task.Title+"»?"))
+		edit := tgbotapi.NewEditMessageReplyMarkup(chatID, callback.Message.MessageID, taskMoveKeyboard(task, columns))
+		if _, err = bot.Send(edit); err != nil {
+			log.Printf("Ошибка обновления кнопок доски: %v", err)
+		}
+
+	case "board_cols_":
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		viewTaskColumns(bot, chatID, id)
+	}
+}
+
+// viewTaskColumns показывает колонки доски группы с кнопками изменения и удаления
+func viewTaskColumns(bot *tgbotapi.BotAPI, chatID int64, idGroup int64) {
+	columns, err := provider.GetTaskColumns(context.Background(), idGroup)
+	if err != nil {
+		log.Printf("Ошибка получения колонок группы %d: %v", idGroup, err)
+		bot.Send(tgbotapi.NewMessage(chatID, "Ошибка при получении колонок доски."))
+		return
+	}
+
+	var inlineKeyboard [][]tgbotapi.InlineKeyboardButton
+	for _, column := range columns {
+		label := "✏️ " + column.Name
+		if column.IsDone {
+			label += " ✅"
+		}
+		inlineKeyboard = append(inlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(label, fmt.Sprintf("tcol_edit_%d", column.IDColumn)),
+			tgbotapi.NewInlineKeyboardButtonData("🗑", fmt.Sprintf("tcol_del_%d", column.IDColumn)),
+		))
+	}
+	inlineKeyboard = append(inlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("➕ Добавить колонку", fmt.Sprintf("tcol_add_%d", idGroup)),
+	))
+
+	msg := tgbotapi.NewMessage(chatID, "Колонки доски. Нажмите на колонку, чтобы переименовать её. "+
+		"Задачи в колонках с ✅ считаются выполненными, новые колонки добавляются перед ними:")
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(inlineKeyboard...)
+	bot.Send(msg)
+}
+
+// handleTaskColumnCallback обрабатывает кнопки управления колонками доски
+func handleTaskColumnCallback(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+	data := callback.Data
+
+	var prefix string
+	for _, p := range []string{"tcol_add_", "tcol_edit_", "tcol_del_"} {
+		if strings.HasPrefix(data, p) {
+			prefix = p
+			break
+		}
+	}
+	id, err := strconv.ParseInt(strings.TrimPrefix(data, prefix), 10, 64)
+	if prefix == "" || err != nil {
+		log.Printf("Некорректные данные кнопки колонки: %s", data)
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Неизвестное действие"))
+		return
+	}
+
+	switch prefix {
+	case "tcol_add_":
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		taskColumnTarget[chatID] = id
+		userSteps[chatID] = "creating_task_column"
+		sendInputPrompt(bot, chatID, "Введите название новой колонки:")
+
+	case "tcol_edit_":
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		taskColumnTarget[chatID] = id
+		userSteps[chatID] = "renaming_task_column"
+		sendInputPrompt(bot, chatID, "Введите новое название колонки:")
+
+	case "tcol_del_":
+		column, err := provider.GetTaskColumn(context.Background(), id)
+		if err != nil {
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Колонка не найдена."))
+			return
+		}
+		if err = provider.DeleteTaskColumn(context.Background(), chatID, id); err != nil {
+			log.Printf("Ошибка удаления колонки ID %d: %v", id, err)
+			bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+			bot.Send(tgbotapi.NewMessage(chatID, "Не удалось удалить колонку: "+err.Error()))
+			return
+		}
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Колонка удалена."))
+		viewTaskColumns(bot, chatID, column.IDGroup)
+	}
+}
+
+// handleTaskColumnInput сохраняет новую колонку доски или новое название существующей
+func handleTaskColumnInput(bot *tgbotapi.BotAPI, chatID int64, text string) {
+	target, ok := taskColumnTarget[chatID]
+	if !ok || text == "Главное меню" {
+		delete(taskColumnTarget, chatID)
+		delete(userSteps, chatID)
+		sendMainMenu(bot, chatID)
+		return
+	}
+
+	ctx := context.Background()
+	var (
+		idGroup int64
+		err     error
+	)
+	switch userSteps[chatID] {
+	case "creating_task_column":
+		idGroup = target
+		err = provider.CreateTaskColumn(ctx, chatID, idGroup, text)
+	case "renaming_task_column":
+		var column gorm_models2.TaskColumn
+		if column, err = provider.GetTaskColumn(ctx, target); err == nil {
+			idGroup = column.IDGroup
+			err = provider.RenameTaskColumn(ctx, chatID, target, text)
+		}
+	}
+	if err != nil {
+		log.Printf("Ошибка сохранения колонки доски: %v", err)
+		bot.Send(tgbotapi.NewMessage(chatID, "Не удалось сохранить колонку: "+err.Error()))
+		return
+	}
+
+	delete(taskColumnTarget, chatID)
+	delete(userSteps, chatID)
+	bot.Send(tgbotapi.NewMessage(chatID, "Колонка сохранена."))
+	sendTasksMenu(bot, chatID)
+	viewTaskBoard(bot, chatID, idGroup)
+}
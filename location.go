@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"aliorToDoBot/src/db"
+	gorm_models2 "aliorToDoBot/src/db/gorm_models"
+)
+
+// ---- Функционал места проведения мероприятий ----
+
+const maxLocationLength = 200
+
+// locationKeyboard возвращает клавиатуру шага ввода места с кнопкой отправки геопозиции
+func locationKeyboard(skipLabel string) tgbotapi.ReplyKeyboardMarkup {
+	return tgbotapi.ReplyKeyboardMarkup{
+		Keyboard: [][]tgbotapi.KeyboardButton{
+			{tgbotapi.NewKeyboardButtonLocation("📍 Отправить геопозицию")},
+			{tgbotapi.NewKeyboardButton(skipLabel), tgbotapi.NewKeyboardButton("Главное меню")},
+		},
+		ResizeKeyboard: true,
+	}
+}
+
+// parseLocationText принимает адрес, введённый текстом. Координаты для него не известны.
+func parseLocationText(text string) (gorm_models2.EventLocation, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return gorm_models2.EventLocation{}, fmt.Errorf("адрес не может быть пустым")
+	}
+	if utf8.RuneCountInString(text) > maxLocationLength {
+		return gorm_models2.EventLocation{}, fmt.Errorf("адрес должен быть не длиннее %d символов", maxLocationLength)
+	}
+	return gorm_models2.EventLocation{LocationTitle: text}, nil
+}
+
+// locationFromMessage возвращает место из присланной геопозиции или точки на карте
+func locationFromMessage(message *tgbotapi.Message) (gorm_models2.EventLocation, bool) {
+	switch {
+	case message.Venue != nil:
+		latitude, longitude := message.Venue.Location.Latitude, message.Venue.Location.Longitude
+		return gorm_models2.EventLocation{
+			LocationTitle:   message.Venue.Title,
+			LocationAddress: message.Venue.Address,
+			Latitude:        &latitude,
+			Longitude:       &longitude,
+		}, true
+	case message.Location != nil:
+		latitude, longitude := message.Location.Latitude, message.Location.Longitude
+		return gorm_models2.EventLocation{Latitude: &latitude, Longitude: &longitude}, true
+	}
+	return gorm_models2.EventLocation{}, false
+}
+
+// formatLocation возвращает место мероприятия одной строкой
+func formatLocation(location gorm_models2.EventLocation) string {
+	var parts []string
+	for _, part := range []string{location.LocationTitle, location.LocationAddress} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) == 0 {
+		return "точка на карте"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// venueButton возвращает кнопку, которая присылает место мероприятия на карте
+func venueButton(event gorm_models2.Event) (tgbotapi.InlineKeyboardButton, bool) {
+	if !event.HasCoordinates() {
+		return tgbotapi.InlineKeyboardButton{}, false
+	}
+	return tgbotapi.NewInlineKeyboardButtonData("📍 На карте", fmt.Sprintf("venue_%d", event.IDEvent)), true
+}
+
+// sendEventVenue присылает место мероприятия точкой на карте, если известны координаты
+func sendEventVenue(bot *tgbotapi.BotAPI, chatID int64, event gorm_models2.Event) error {
+	if !event.HasCoordinates() {
+		return nil
+	}
+	// Telegram требует адрес у места, поэтому точка без адреса отправляется простой геопозицией
+	if event.LocationAddress == "" {
+		_, err := bot.Send(tgbotapi.NewLocation(chatID, *event.Latitude, *event.Longitude))
+		return err
+	}
+	title := event.LocationTitle
+	if title == "" {
+		title = event.NameEvent
+	}
+	venue := tgbotapi.NewVenue(chatID, title, event.LocationAddress, *event.Latitude, *event.Longitude)
+	_, err := bot.Send(venue)
+	return err
+}
+
+// handleVenueCallback присылает место мероприятия по кнопке из карточки
+func handleVenueCallback(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+
+	eventID, err := strconv.ParseInt(strings.TrimPrefix(callback.Data, "venue_"), 10, 64)
+	if err != nil {
+		log.Printf("Ошибка преобразования ID мероприятия: %v", err)
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Некорректный ID мероприятия."))
+		return
+	}
+	var event gorm_models2.Event
+	if err = db.DB.First(&event, eventID).Error; err != nil {
+		log.Printf("Ошибка получения мероприятия: %v", err)
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Мероприятие не найдено."))
+		return
+	}
+	// Место мероприятия видят только участники его группы
+	if err = provider.CheckGroupMember(context.Background(), chatID, event.IDGroup); err != nil {
+		log.Printf("Пользователь %d не состоит в группе мероприятия ID %d: %v", chatID, eventID, err)
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Мероприятие не найдено."))
+		return
+	}
+	bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+	if err = sendEventVenue(bot, chatID, event); err != nil {
+		log.Printf("Ошибка отправки места мероприятия ID %d: %v", eventID, err)
+	}
+}
+
+// handleLocationMessage принимает геопозицию или точку на карте на шагах ввода места
+func handleLocationMessage(bot *tgbotapi.BotAPI, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	location, _ := locationFromMessage(message)
+
+	switch userSteps[chatID] {
+	case "creating_event_location":
+		event := tempEvent[chatID]
+		event.EventLocation = location
+		tempEvent[chatID] = event
+		askEventRecurrence(bot, chatID)
+
+	case "editing_event_location":
+		event, ok := editEvent[chatID]
+		if !ok {
+			delete(userSteps, chatID)
+			return
+		}
+		event.EventLocation = location
+		editEvent[chatID] = event
+		delete(userSteps, chatID)
+		sendEditEventMenu(bot, chatID)
+
+	default:
+		bot.Send(tgbotapi.NewMessage(chatID, "Место можно указать при создании или редактировании мероприятия."))
+	}
+}
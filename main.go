@@ -14,6 +14,8 @@ import (
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"gorm.io/gorm"
 
+	"aliorToDoBot/src/config"
+	"aliorToDoBot/src/dateparse"
 	"aliorToDoBot/src/db"
 	gorm_models2 `aliorToDoBot/src/db/gorm_models`
 )
@@ -22,26 +24,78 @@ var (
 	userSteps = make(map[int64]string)
 	tempEvent = make(map[int64]gorm_models2.Event) // Временное хранилище для событий на этапе создания
 	tempGroup = make(map[int64]gorm_models2.Group) // Временное хранилище для групп на этапе создания
+	editEvent = make(map[int64]gorm_models2.Event) // Временное хранилище для событий на этапе редактирования
+
+	tempEventReminders = make(map[int64]string) // Интервалы напоминаний создаваемого события
+	tempEventTemplate  = make(map[int64]int64)  // Шаблон, по которому создаётся событие: спрашивается только дата
+	rescheduledEvent   = make(map[int64]bool)   // Время события выбирается заново после пересечения: остальные поля уже заполнены
+
+	provider     *db.GormProvider
+	trashConfig  config.TrashConfig // Время отмены удаления и срок хранения корзины
+	listPageSize int                // Количество записей на странице списков
+	sessionTTL   time.Duration      // Время, в течение которого открытое мероприятие принимает присланные файлы
 )
 
 func main() {
 
+	// Загружаем конфигурацию
+	cfg, err := config.LoadConfig("config/config.yaml")
+	if err != nil {
+		log.Printf("Ошибка загрузки конфигурации, используются значения по умолчанию: %v", err)
+		cfg = config.DefaultConfig()
+	}
+	cfg.ParseEnv()
+	trashConfig = cfg.Trash
+	listPageSize = cfg.UI.PageSize
+	sessionTTL = cfg.UI.SessionTTL
+
 	// Строка подключения к PostgreSQL
 	dsn := "host=localhost user=postgres password=password dbname=AliorToDoBot port=5432 sslmode=disable"
 
 	// Инициализация базы данных через GORM
 	db.InitGormDatabase(dsn)
+	provider = &db.GormProvider{DB: db.DB}
+
+	// Время, сохранённое до появления часовых поясов, пересчитывается до автоматической миграции
+	if err = db.ConvertLegacyTimestamps(db.DB); err != nil {
+		log.Fatalf("Ошибка перевода времени мероприятий в формат с часовым поясом: %v", err)
+	}
+	if err = db.AddAttendanceOccurrence(db.DB); err != nil {
+		log.Fatalf("Ошибка добавления повторений к ответам участников: %v", err)
+	}
 
 	// Автоматическая миграция моделей
-	err := db.DB.AutoMigrate(
+	err = db.DB.AutoMigrate(
 		&gorm_models2.User{},
 		&gorm_models2.Group{},
 		&gorm_models2.Event{},
 		&gorm_models2.Membership{},
+		&gorm_models2.EventException{},
+		&gorm_models2.SentReminder{},
+		&gorm_models2.ReminderPreference{},
+		&gorm_models2.Category{},
+		&gorm_models2.Attendance{},
+		&gorm_models2.ChecklistItem{},
+		&gorm_models2.Task{},
+		&gorm_models2.TaskAssignee{},
+		&gorm_models2.TaskColumn{},
+		&gorm_models2.EventComment{},
+		&gorm_models2.EventAttachment{},
+		&gorm_models2.EventTemplate{},
+		&gorm_models2.DigestSetting{},
 	)
 	if err != nil {
 		log.Fatalf("Ошибка миграции: %v", err)
 	}
+	if err = db.RefreshEventStatusCheck(db.DB); err != nil {
+		log.Fatalf("Ошибка обновления проверки статусов мероприятий: %v", err)
+	}
+	if err = db.DropEventCategoryCheck(db.DB); err != nil {
+		log.Fatalf("Ошибка удаления проверки категорий мероприятий: %v", err)
+	}
+	if err = db.BackfillGroupCategories(db.DB); err != nil {
+		log.Fatalf("Ошибка добавления категорий существующим группам: %v", err)
+	}
 
 	log.Println("База данных успешно инициализирована и обновлена!")
 
@@ -53,6 +107,13 @@ func main() {
 	bot.Debug = true
 	log.Printf("Авторизован как %s", bot.Self.UserName)
 
+	// Фоновая рассылка напоминаний о мероприятиях
+	go startReminderScheduler(context.Background(), bot, &cfg.Reminders)
+	// Фоновая очистка корзины от мероприятий с истёкшим сроком хранения
+	go startTrashPurger(context.Background(), &cfg.Trash)
+	// Фоновая рассылка ежедневных сводок
+	go startDigestScheduler(context.Background(), bot, &cfg.Digest)
+
 	u := tgbotapi.NewUpdate(0)
 	u.Timeout = 60
 	updates := bot.GetUpdatesChan(u)
@@ -69,11 +130,51 @@ func main() {
 			chatID := update.Message.Chat.ID
 			userStep := userSteps[chatID]
 
+			// Геопозиция и точка на карте задают место мероприятия
+			if update.Message.Location != nil || update.Message.Venue != nil {
+				handleLocationMessage(bot, update.Message)
+				continue
+			}
+
+			// Документы и фото прикрепляются к открытому мероприятию
+			if update.Message.Document != nil || len(update.Message.Photo) > 0 {
+				handleAttachmentMessage(bot, update.Message)
+				continue
+			}
+
 			switch userStep {
-			case "creating_event_category", "creating_event_name", "creating_event_time", "creating_event_duration", "creating_event_all_day_date":
+			case "creating_event_category", "creating_event_name", "creating_event_time", "creating_event_duration", "creating_event_all_day_date", "creating_event_recurrence",
+				"creating_event_reminders", "creating_event_link", "creating_event_location", "confirming_event_time", "resolving_event_conflicts":
 				handleEventCreation(bot, chatID, update.Message.Text)
 			case "creating_group_name", "adding_group_members":
 				handleGroupCreation(bot, chatID, update.Message.Text)
+			case "editing_event_name", "editing_event_category", "editing_event_time", "editing_event_all_day_date", "editing_event_duration",
+				"editing_event_link", "editing_event_location", "confirming_edit_time":
+				handleEventEditing(bot, chatID, update.Message.Text)
+			case "moving_occurrence", "confirming_occurrence_move":
+				handleOccurrenceMove(bot, chatID, update.Message.Text)
+			case "postponing_event":
+				handlePostponeInput(bot, chatID, update.Message.Text)
+			case "search_query", "search_period":
+				handleSearchInput(bot, chatID, update.Message.Text)
+			case "adding_event_comment":
+				handleCommentInput(bot, chatID, update.Message.Text)
+			case "naming_template":
+				handleTemplateNameInput(bot, chatID, update.Message.Text)
+			case "setting_reminder_offsets":
+				handleReminderSettingsInput(bot, chatID, update.Message.Text)
+			case "setting_digest_time":
+				handleDigestTimeInput(bot, chatID, update.Message.Text)
+			case "setting_time_zone":
+				handleTimeZoneInput(bot, chatID, update.Message.Text)
+			case "creating_category", "renaming_category":
+				handleCategoryInput(bot, chatID, update.Message.Text)
+			case "adding_checklist_items":
+				handleChecklistInput(bot, chatID, update.Message.Text)
+			case "selecting_task_group", "creating_task_title", "creating_task_deadline", "creating_task_priority":
+				handleTaskCreation(bot, chatID, update.Message.Text)
+			case "creating_task_column", "renaming_task_column":
+				handleTaskColumnInput(bot, chatID, update.Message.Text)
 			default:
 				u.handleDefault(bot, chatID, update.Message.Text, update.Message.Chat.UserName)
 			}
@@ -144,17 +245,27 @@ func (u *ui) handleDefault( chatID int64, text, username string) {
 
 	if text == "Главное меню" {
 		delete(userSteps, chatID)
+		delete(viewedEvent, chatID)
 		u.sendMainMenu()
 		return
 	}
 
+	// Команда поиска может содержать текст запроса: /find отчёт
+	if text == "/find" || strings.HasPrefix(text, "/find ") {
+		startSearch(bot, chatID, strings.TrimPrefix(text, "/find"))
+		return
+	}
+
 	switch text {
 	case "/start":
 		checkAndAddNewUser(username, chatID)
 		ensurePersonalGroup(chatID)
 		u.sendMainMenu()
+		askTimeZone(bot, chatID)
 	case "Мероприятия":
 		sendEventsMenu(bot, chatID)
+	case "Задачи":
+		sendTasksMenu(bot, chatID)
 	case "Группы":
 		sendGroupsMenu(bot, chatID)
 	case "Создать мероприятие":
@@ -164,12 +275,42 @@ func (u *ui) handleDefault( chatID int64, text, username string) {
 	case "Мои мероприятия":
 		UpdateEventStatuses(db.DB)
 		viewMyEvents(bot, chatID)
+	case "Создать задачу":
+		startCreateTask(bot, chatID)
+	case "Мои задачи":
+		viewMyTasks(bot, chatID)
+	case "Назначено мне":
+		viewAssignedTasks(bot, chatID)
+	case "Доска":
+		viewBoardGroups(bot, chatID)
+	case "Поиск":
+		startSearch(bot, chatID, "")
+	case "Из шаблона":
+		viewTemplates(bot, chatID)
+	case "Корзина":
+		viewTrash(bot, chatID)
+	case "/today", "Сегодня":
+		viewAgenda(bot, chatID, agendaDay)
+	case "/week", "Неделя":
+		viewAgenda(bot, chatID, agendaWeek)
+	case "/month", "Месяц":
+		viewAgenda(bot, chatID, agendaMonth)
 	case "Удалить мероприятие":
 		deleteEvent(bot, chatID)
 	case "Мои группы":
 		viewMyGroups(bot, chatID)
 	case "Выйти из группы":
 		leaveGroup(bot, chatID)
+	case "Настройки":
+		sendSettingsMenu(bot, chatID)
+	case "Напоминания":
+		viewReminderSettings(bot, chatID)
+	case "Сводка":
+		viewDigestSettings(bot, chatID)
+	case "Часовой пояс":
+		askTimeZone(bot, chatID)
+	case "Категории":
+		viewCategoryGroups(bot, chatID)
 	default:
 		msg := tgbotapi.NewMessage(chatID, "Неизвестная команда. Используйте /start.")
 		bot.Send(msg)
@@ -231,6 +372,12 @@ func  checkPersonalGroup( ) {
 		bot.Send(msg)
 		return
 	}
+	if err = provider.CreateDefaultCategories(context.Background(), newGroup.IDGroup); err != nil {
+		log.Printf("Ошибка создания категорий группы 'Личное' для пользователя %d: %v", user.IDUser, err)
+	}
+	if err = provider.CreateDefaultTaskColumns(context.Background(), newGroup.IDGroup); err != nil {
+		log.Printf("Ошибка создания колонок доски группы 'Личное' для пользователя %d: %v", user.IDUser, err)
+	}
 
 	// Добавляем запись о членстве (Membership) для администратора
 	membership := gorm_models2.Membership{
@@ -253,7 +400,8 @@ func (u *ui) sendMainMenu() {
 	msg := tgbotapi.NewMessage(u.chatID, "Главное меню:")
 	msg.ReplyMarkup = tgbotapi.ReplyKeyboardMarkup{
 		Keyboard: [][]tgbotapi.KeyboardButton{
-			{tgbotapi.NewKeyboardButton("Мероприятия"), tgbotapi.NewKeyboardButton("Группы")},
+			{tgbotapi.NewKeyboardButton("Мероприятия"), tgbotapi.NewKeyboardButton("Задачи"), tgbotapi.NewKeyboardButton("Группы")},
+			{tgbotapi.NewKeyboardButton("Настройки")},
 		},
 		ResizeKeyboard: true,
 	}
@@ -264,7 +412,9 @@ func sendEventsMenu(bot *tgbotapi.BotAPI, chatID int64) {
 	msg := tgbotapi.NewMessage(chatID, "Меню мероприятий:")
 	msg.ReplyMarkup = tgbotapi.ReplyKeyboardMarkup{
 		Keyboard: [][]tgbotapi.KeyboardButton{
-			{tgbotapi.NewKeyboardButton("Создать мероприятие")},
+			{tgbotapi.NewKeyboardButton("Создать мероприятие"), tgbotapi.NewKeyboardButton("Из шаблона")},
+			{tgbotapi.NewKeyboardButton("Поиск"), tgbotapi.NewKeyboardButton("Корзина")},
+			{tgbotapi.NewKeyboardButton("Сегодня"), tgbotapi.NewKeyboardButton("Неделя"), tgbotapi.NewKeyboardButton("Месяц")},
 			{tgbotapi.NewKeyboardButton("Главное меню"), tgbotapi.NewKeyboardButton("Мои мероприятия")},
 		},
 		ResizeKeyboard: true,
@@ -277,6 +427,7 @@ func sendGroupsMenu(bot *tgbotapi.BotAPI, chatID int64) {
 	msg.ReplyMarkup = tgbotapi.ReplyKeyboardMarkup{
 		Keyboard: [][]tgbotapi.KeyboardButton{
 			{tgbotapi.NewKeyboardButton("Создать группу"), tgbotapi.NewKeyboardButton("Мои группы")},
+			{tgbotapi.NewKeyboardButton("Категории")},
 			{tgbotapi.NewKeyboardButton("Главное меню")},
 		},
 		ResizeKeyboard: true,
@@ -285,103 +436,147 @@ func sendGroupsMenu(bot *tgbotapi.BotAPI, chatID int64) {
 }
 
 // ---- Функционал просмотра мероприятий ----
-func viewMyEvents(bot *tgbotapi.BotAPI, chatID int64) {
-	// Извлекаем IDUser из таблицы users по chatID
-	var user gorm_models2.User
-	if err := db.DB.Where("id_chat = ?", chatID).First(&user).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			log.Printf("Пользователь с chatID %d не найден", chatID)
-			msg := tgbotapi.NewMessage(chatID, "Ваш пользовательский профиль не найден. Обратитесь в поддержку.")
-			bot.Send(msg)
-			return
-		}
-		log.Printf("Ошибка извлечения пользователя с chatID %d: %v", chatID, err)
-		msg := tgbotapi.NewMessage(chatID, "Произошла ошибка. Попробуйте позже.")
-		bot.Send(msg)
-		return
-	}
 
-	// Найти группы, в которых пользователь состоит
-	var memberships []gorm_models2.Membership
-	if err := db.DB.Where("id_user = ?", user.IDUser).Find(&memberships).Error; err != nil {
-		log.Println("Ошибка получения групп пользователя:", err)
-		msg := tgbotapi.NewMessage(chatID, "Ошибка при получении ваших групп.")
-		bot.Send(msg)
-		return
+// eventsPage формирует страницу мероприятий пользователя с кнопками карточек и листания.
+// По умолчанию показываются только предстоящие мероприятия.
+func eventsPage(chatID int64, upcomingOnly bool, offset int) (string, tgbotapi.InlineKeyboardMarkup, error) {
+	ctx := context.Background()
+	events, total, err := provider.GetUserEventsPage(ctx, chatID, upcomingOnly, listPageSize, offset)
+	if err != nil {
+		return "", tgbotapi.InlineKeyboardMarkup{}, err
 	}
-
-	if len(memberships) == 0 {
-		msg := tgbotapi.NewMessage(chatID, "У вас пока нет мероприятий.")
-		bot.Send(msg)
-		return
+	// После удаления мероприятий страницы может уже не быть: показываем последнюю
+	if len(events) == 0 && offset > 0 && total > 0 {
+		return eventsPage(chatID, upcomingOnly, lastPageOffset(total))
 	}
 
-	// Собираем ID групп
-	groupIDs := make([]int64, 0)
-	for _, membership := range memberships {
-		groupIDs = append(groupIDs, membership.IDGroup)
+	filter, toggle := "u", tgbotapi.NewInlineKeyboardButtonData("Показать все", "events_page_a_0")
+	if !upcomingOnly {
+		filter, toggle = "a", tgbotapi.NewInlineKeyboardButtonData("Только предстоящие", "events_page_u_0")
 	}
-
-	// Находим мероприятия, связанные с этими группами
-	var events []gorm_models2.Event
-	if err := db.DB.Where("id_group IN ?", groupIDs).Find(&events).Error; err != nil {
-		log.Println("Ошибка получения event записей:", err)
-		msg := tgbotapi.NewMessage(chatID, "Ошибка при получении ваших мероприятий.")
-		bot.Send(msg)
-		return
-	}
-
-	if len(events) == 0 {
-		msg := tgbotapi.NewMessage(chatID, "У вас пока нет мероприятий.")
-		bot.Send(msg)
-		return
+	if total == 0 {
+		text := "У вас пока нет мероприятий."
+		if upcomingOnly {
+			text = "Предстоящих мероприятий нет."
+		}
+		return text, tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(toggle)), nil
 	}
 
-	// Извлекаем данные о группах для отображения
-	var groups []gorm_models2.Group
-	if err := db.DB.Where("id_group IN ?", groupIDs).Find(&groups).Error; err != nil {
-		log.Println("Ошибка получения данных групп:", err)
-		msg := tgbotapi.NewMessage(chatID, "Ошибка при обработке данных ваших групп.")
-		bot.Send(msg)
-		return
+	groups, err := provider.GetUserGroups(ctx, chatID)
+	if err != nil {
+		return "", tgbotapi.InlineKeyboardMarkup{}, err
 	}
-
 	groupMap := make(map[int64]string)
 	for _, group := range groups {
 		groupMap[group.IDGroup] = group.GroupName
 	}
 
-	// Формируем список мероприятий для отображения
+	exceptions := loadExceptions(events)
+	loc := userLocation(chatID)
+	now := time.Now()
+
 	var message strings.Builder
-	message.WriteString("Ваши мероприятия:\n\n")
+	title := "Предстоящие мероприятия"
+	if !upcomingOnly {
+		title = "Все мероприятия"
+	}
+	message.WriteString(fmt.Sprintf("%s: %d\n\n", title, total))
+
+	var inlineKeyboard [][]tgbotapi.InlineKeyboardButton
+	shown := 0
 	for _, event := range events {
-		groupName := groupMap[event.IDGroup]
-		message.WriteString(formatEvent(event, groupName) + "\n\n")
+		block := formatEvent(event, groupMap[event.IDGroup], loc)
+		if event.RecurFreq != "" {
+			upcoming := nextOccurrences(event, exceptions[event.IDEvent], now, 3)
+			if len(upcoming) > 0 {
+				dates := make([]string, 0, len(upcoming))
+				for _, occ := range upcoming {
+					dates = append(dates, formatOccurrenceStart(occ.Event, loc))
+				}
+				block += "\nБлижайшие: " + strings.Join(dates, ", ")
+			}
+		}
+		if !addPageRecord(&message, block+"\n\n", shown == 0, true) {
+			break
+		}
+		shown++
+
+		// Инлайн-кнопки для перехода к карточке мероприятия
+		row := tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📅 "+event.NameEvent, fmt.Sprintf("event_card_%d", event.IDEvent)),
+		)
+		if button, ok := meetingLinkButton(event); ok {
+			row = append(row, button)
+		}
+		inlineKeyboard = append(inlineKeyboard, row)
 	}
 
-	// Отправляем сообщение с клавиатурой
-	msg := tgbotapi.NewMessage(chatID, message.String())
-	msg.ParseMode = "Markdown"
-	msg.ReplyMarkup = tgbotapi.ReplyKeyboardMarkup{
+	if pager := pagerRow("events_page_"+filter+"_", offset, shown, total); len(pager) > 0 {
+		inlineKeyboard = append(inlineKeyboard, pager)
+	}
+	inlineKeyboard = append(inlineKeyboard, tgbotapi.NewInlineKeyboardRow(toggle))
+	return message.String(), tgbotapi.NewInlineKeyboardMarkup(inlineKeyboard...), nil
+}
+
+// viewMyEvents отправляет первую страницу предстоящих мероприятий пользователя
+func viewMyEvents(bot *tgbotapi.BotAPI, chatID int64) {
+	text, keyboard, err := eventsPage(chatID, true, 0)
+	if err != nil {
+		log.Printf("Ошибка получения мероприятий пользователя %d: %v", chatID, err)
+		bot.Send(tgbotapi.NewMessage(chatID, "Ошибка при получении ваших мероприятий: "+err.Error()))
+		return
+	}
+
+	// Клавиатура меню отправляется отдельным сообщением: к странице списка привязаны кнопки листания
+	menu := tgbotapi.NewMessage(chatID, "Ваши мероприятия. Откройте мероприятие, чтобы ответить, придёте ли вы, или изменить его.")
+	menu.ReplyMarkup = tgbotapi.ReplyKeyboardMarkup{
 		Keyboard: [][]tgbotapi.KeyboardButton{
 			{tgbotapi.NewKeyboardButton("Удалить мероприятие")},
 			{tgbotapi.NewKeyboardButton("Главное меню")},
 		},
 		ResizeKeyboard: true,
 	}
-	bot.Send(msg)
+	if _, err = bot.Send(menu); err != nil {
+		log.Printf("Ошибка отправки сообщения: %v", err)
+	}
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = keyboard
+	if _, err = bot.Send(msg); err != nil {
+		log.Printf("Ошибка отправки списка мероприятий пользователю %d: %v", chatID, err)
+	}
 }
 
-func formatEvent(event gorm_models2.Event, groupName string) string {
+// formatEvent форматирует мероприятие для показа в часовом поясе loc
+func formatEvent(event gorm_models2.Event, groupName string, loc *time.Location) string {
 	// Форматируем продолжительность без секунд
 	formattedDuration := formatDuration(event.Duration)
 
+	// Текст отправляется в разметке Markdown: названия могут содержать _ и *
+	name := tgbotapi.EscapeText(tgbotapi.ModeMarkdown, event.NameEvent)
+	groupName = tgbotapi.EscapeText(tgbotapi.ModeMarkdown, groupName)
+	category := tgbotapi.EscapeText(tgbotapi.ModeMarkdown, event.Category)
+
+	var result string
 	if event.IsAllDay {
-		return fmt.Sprintf("📅 *%s*\nГруппа: %s\nКатегория: %s\nДата: %s\nСтатус: %s",
-			event.NameEvent, groupName, event.Category, event.DatetimeStart.Format("02.01.2006"), event.Status)
+		result = fmt.Sprintf("📅 *%s*\nГруппа: %s\nКатегория: %s\nДата: %s\nСтатус: %s",
+			name, groupName, category, formatOccurrenceStart(event, loc), event.Status)
+	} else {
+		result = fmt.Sprintf("📅 *%s*\nГруппа: %s\nКатегория: %s\nДата и время: %s\nПродолжительность: %s\nСтатус: %s",
+			name, groupName, category, formatOccurrenceStart(event, loc), formattedDuration, event.Status)
+	}
+
+	if !event.EventLocation.IsEmpty() {
+		result += "\nМесто: " + tgbotapi.EscapeText(tgbotapi.ModeMarkdown, formatLocation(event.EventLocation))
+	}
+	if event.RecurFreq != "" {
+		result += "\nПовтор: " + eventRule(event).Describe()
+	}
+	if len(event.Checklist) > 0 {
+		result += "\nЧек-лист: " + checklistProgress(event.Checklist)
 	}
-	return fmt.Sprintf("📅 *%s*\nГруппа: %s\nКатегория: %s\nДата и время: %s\nПродолжительность: %s\nСтатус: %s",
-		event.NameEvent, groupName, event.Category, event.DatetimeStart.Format("02.01.2006 15:04"), formattedDuration, event.Status)
+	return result
 }
 
 // Функция форматирования продолжительности без секунд
@@ -459,60 +654,29 @@ func deleteEvent(bot *tgbotapi.BotAPI, chatID int64) {
 }
 
 // ---- Функционал просмотра групп ----
-func viewMyGroups(bot *tgbotapi.BotAPI, chatID int64) {
-	// Извлекаем IDUser из таблицы users по chatID
-	var user gorm_models2.User
-	if err := db.DB.Where("id_chat = ?", chatID).First(&user).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			log.Printf("Пользователь с chatID %d не найден", chatID)
-			bot.Send(tgbotapi.NewMessage(chatID, "Ваш пользовательский профиль не найден. Обратитесь в поддержку."))
-			return
-		}
-		log.Printf("Ошибка извлечения пользователя с chatID %d: %v", chatID, err)
-		bot.Send(tgbotapi.NewMessage(chatID, "Произошла ошибка. Попробуйте позже."))
-		return
-	}
 
-	// Получение записей Membership для пользователя
-	var memberships []gorm_models2.Membership
-	if err := db.DB.Where("id_user = ?", user.IDUser).Find(&memberships).Error; err != nil {
-		log.Println("Ошибка получения membership записей:", err)
-		msg := tgbotapi.NewMessage(chatID, "Ошибка при получении ваших групп.")
-		bot.Send(msg)
-		return
-	}
+const noGroupsText = "У вас пока нет групп."
 
-	// Проверка, есть ли группы у пользователя
-	if len(memberships) == 0 {
-		msg := tgbotapi.NewMessage(chatID, "У вас пока нет групп.")
-		msg.ReplyMarkup = tgbotapi.ReplyKeyboardMarkup{
-			Keyboard: [][]tgbotapi.KeyboardButton{
-				{tgbotapi.NewKeyboardButton("Главное меню")},
-			},
-			ResizeKeyboard: true,
-		}
-		bot.Send(msg)
-		return
+// groupsPage формирует страницу групп пользователя с администратором и участниками каждой группы
+func groupsPage(chatID int64, offset int) (string, tgbotapi.InlineKeyboardMarkup, error) {
+	groups, total, err := provider.GetUserGroupsPage(context.Background(), chatID, listPageSize, offset)
+	if err != nil {
+		return "", tgbotapi.InlineKeyboardMarkup{}, err
 	}
-
-	// Получение ID групп из Membership
-	groupIDs := make([]int64, 0)
-	for _, membership := range memberships {
-		groupIDs = append(groupIDs, membership.IDGroup)
+	// После выхода из групп страницы может уже не быть: показываем последнюю
+	if len(groups) == 0 && offset > 0 && total > 0 {
+		return groupsPage(chatID, lastPageOffset(total))
 	}
-
-	// Получение информации о группах
-	var groups []gorm_models2.Group
-	if err := db.DB.Where("id_group IN ?", groupIDs).Find(&groups).Error; err != nil {
-		log.Println("Ошибка получения групп:", err)
-		msg := tgbotapi.NewMessage(chatID, "Ошибка при получении ваших групп.")
-		bot.Send(msg)
-		return
+	// Пустой, но не nil список строк: при обновлении сообщения он убирает кнопки листания
+	keyboard := tgbotapi.InlineKeyboardMarkup{InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{}}
+	if total == 0 {
+		return noGroupsText, keyboard, nil
 	}
 
 	// Формирование сообщения с информацией о группах
 	var message strings.Builder
-	message.WriteString("Ваши группы:\n\n")
+	message.WriteString(fmt.Sprintf("Ваши группы: %d\n\n", total))
+	shown := 0
 	for _, group := range groups {
 		// Получение всех участников группы
 		var groupMemberships []gorm_models2.Membership
@@ -528,7 +692,7 @@ func viewMyGroups(bot *tgbotapi.BotAPI, chatID int64) {
 		for _, membership := range groupMemberships {
 			var groupUser gorm_models2.User
 			if err := db.DB.Where("id_user = ?", membership.IDUser).First(&groupUser).Error; err == nil {
-				if membership.IDAdmin == membership.IDUser {
+				if membership.IsAdmin {
 					admin = "@" + groupUser.UserName
 				} else {
 					members = append(members, "@"+groupUser.UserName)
@@ -545,28 +709,70 @@ func viewMyGroups(bot *tgbotapi.BotAPI, chatID int64) {
 		}
 
 		// Добавление информации о группе в сообщение
-		message.WriteString(fmt.Sprintf(
+		if !addPageRecord(&message, fmt.Sprintf(
 			"Группа: %s\nАдминистратор: %s\nУчастники: %s\n\n",
 			group.GroupName,
 			admin,
 			strings.Join(members, ", "),
-		))
+		), shown == 0, false) {
+			break
+		}
+		shown++
 	}
 
-	// Отправка сообщения с информацией о группах
-	msg := tgbotapi.NewMessage(chatID, message.String())
-	msg.ReplyMarkup = tgbotapi.ReplyKeyboardMarkup{
+	if pager := pagerRow("groups_page_", offset, shown, total); len(pager) > 0 {
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, pager)
+	}
+	return message.String(), keyboard, nil
+}
+
+// viewMyGroups отправляет первую страницу групп пользователя
+func viewMyGroups(bot *tgbotapi.BotAPI, chatID int64) {
+	text, keyboard, err := groupsPage(chatID, 0)
+	if err != nil {
+		log.Printf("Ошибка получения групп пользователя %d: %v", chatID, err)
+		bot.Send(tgbotapi.NewMessage(chatID, "Ошибка при получении ваших групп: "+err.Error()))
+		return
+	}
+
+	menuKeyboard := tgbotapi.ReplyKeyboardMarkup{
 		Keyboard: [][]tgbotapi.KeyboardButton{
 			{tgbotapi.NewKeyboardButton("Выйти из группы")},
 			{tgbotapi.NewKeyboardButton("Главное меню")},
 		},
 		ResizeKeyboard: true,
 	}
-	bot.Send(msg)
+	if len(keyboard.InlineKeyboard) == 0 {
+		// Все группы поместились на одну страницу: клавиатура меню прикрепляется к самому списку
+		if text == noGroupsText {
+			menuKeyboard.Keyboard = menuKeyboard.Keyboard[1:]
+		}
+		msg := tgbotapi.NewMessage(chatID, text)
+		msg.ReplyMarkup = menuKeyboard
+		if _, err = bot.Send(msg); err != nil {
+			log.Printf("Ошибка отправки списка групп пользователю %d: %v", chatID, err)
+		}
+		return
+	}
+
+	menu := tgbotapi.NewMessage(chatID, "Ваши группы:")
+	menu.ReplyMarkup = menuKeyboard
+	if _, err = bot.Send(menu); err != nil {
+		log.Printf("Ошибка отправки сообщения: %v", err)
+	}
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyMarkup = keyboard
+	if _, err = bot.Send(msg); err != nil {
+		log.Printf("Ошибка отправки списка групп пользователю %d: %v", chatID, err)
+	}
 }
 
 // ---- Функционал создания мероприятий ----
 func startCreateEvent(bot *tgbotapi.BotAPI, chatID int64) {
+	// Сбрасываем признаки прерванного ранее создания мероприятия
+	delete(tempEventTemplate, chatID)
+	delete(rescheduledEvent, chatID)
+
 	// Извлекаем IDUser из таблицы users по chatID
 	var user gorm_models2.User
 	if err := db.DB.Where("id_chat = ?", chatID).First(&user).Error; err != nil {
@@ -648,6 +854,9 @@ func handleEventCreation(bot *tgbotapi.BotAPI, chatID int64, text string) {
 	event := tempEvent[chatID]
 	if text == "Главное меню" {
 		delete(tempGroup, chatID)
+		delete(tempEventReminders, chatID)
+		delete(tempEventTemplate, chatID)
+		delete(rescheduledEvent, chatID)
 		delete(userSteps, chatID)
 		sendMainMenu(bot, chatID)
 		return
@@ -666,22 +875,21 @@ func handleEventCreation(bot *tgbotapi.BotAPI, chatID int64, text string) {
 		}
 
 	case "creating_event_category":
-		validCategories := []string{"Личное", "Семья", "Работа"}
-		isValid := false
-		for _, category := range validCategories {
-			if text == category {
-				isValid = true
-				break
-			}
+		categories, err := provider.GetGroupCategories(context.Background(), event.IDGroup)
+		if err != nil {
+			log.Printf("Ошибка получения категорий группы %d: %v", event.IDGroup, err)
+			bot.Send(tgbotapi.NewMessage(chatID, "Ошибка при получении категорий группы."))
+			return
 		}
-		if !isValid {
-			msg := tgbotapi.NewMessage(chatID, "Некорректная категория. Пожалуйста, выберите из: Личное, Семья, Работа.")
+		category, ok := matchCategory(categories, text)
+		if !ok {
+			msg := tgbotapi.NewMessage(chatID, "Некорректная категория. Пожалуйста, выберите одну из кнопок.")
 			if _, err := bot.Send(msg); err != nil {
 				log.Printf("Ошибка отправки сообщения: %v", err)
 			}
 			return
 		}
-		event.Category = text
+		event.Category = category.Name
 		tempEvent[chatID] = event
 		userSteps[chatID] = "creating_event_name"
 		log.Printf("Переход к состоянию: %s", userSteps[chatID])
@@ -693,81 +901,110 @@ func handleEventCreation(bot *tgbotapi.BotAPI, chatID int64, text string) {
 	case "creating_event_name":
 		event.NameEvent = text
 		tempEvent[chatID] = event
-		userSteps[chatID] = "creating_event_time"
-		log.Printf("Переход к состоянию: %s", userSteps[chatID])
-		msg := tgbotapi.NewMessage(chatID, "Введите дату и время начала в формате дд.мм.гггг чч:мм или нажмите 'Весь день':")
-		msg.ReplyMarkup = tgbotapi.ReplyKeyboardMarkup{
-			Keyboard: [][]tgbotapi.KeyboardButton{
-				{tgbotapi.NewKeyboardButton("Весь день"), tgbotapi.NewKeyboardButton("Главное меню")},
-			},
-			ResizeKeyboard: true,
-		}
-		if _, err := bot.Send(msg); err != nil {
-			log.Printf("Ошибка отправки сообщения: %v", err)
-		}
+		askEventTime(bot, chatID, "Когда начало? Например: «завтра в 15:00», «в пятницу 10:30», «через 2 часа» "+
+			"или дд.мм.гггг чч:мм. Для мероприятия на весь день нажмите 'Весь день':")
 
 	case "creating_event_time":
 		if strings.HasPrefix(text, "Весь день") {
 			userSteps[chatID] = "creating_event_all_day_date"
 			log.Printf("Переход к состоянию: %s", userSteps[chatID])
-			msg := tgbotapi.NewMessage(chatID, "Введите дату для мероприятия в формате дд.мм.гггг:")
+			msg := tgbotapi.NewMessage(chatID, "Введите дату мероприятия, например «25 декабря», «в субботу» или дд.мм.гггг:")
 			if _, err := bot.Send(msg); err != nil {
 				log.Printf("Ошибка отправки сообщения: %v", err)
 			}
+			sendDatePicker(bot, chatID)
 			return
 		}
-		layout := "02.01.2006 15:04"
-		startTime, err := time.Parse(layout, text)
+		loc := userLocation(chatID)
+		startTime, hasTime, err := dateparse.Parse(text, time.Now().In(loc))
 		if err != nil {
-			msg := tgbotapi.NewMessage(chatID, "Неверный формат. Пожалуйста, введите дату и время в формате дд.мм.гггг чч:мм.")
+			log.Printf("Ошибка парсинга даты '%s': %v", text, err)
+			msg := tgbotapi.NewMessage(chatID, "Не удалось распознать дату и время. Попробуйте, например, «завтра в 15:00», "+
+				"«в пятницу 10:30», «через 2 часа» или дд.мм.гггг чч:мм.")
 			if _, err := bot.Send(msg); err != nil {
 				log.Printf("Ошибка отправки сообщения: %v", err)
 			}
 			return
 		}
-		event.DatetimeStart = startTime.UTC()
-		tempEvent[chatID] = event
-		userSteps[chatID] = "creating_event_duration"
-		log.Printf("Переход к состоянию: %s", userSteps[chatID])
-		msg := tgbotapi.NewMessage(chatID, "Введите продолжительность мероприятия (например, 1d2h) или нажмите 'Пропустить':")
-		msg.ReplyMarkup = tgbotapi.ReplyKeyboardMarkup{
-			Keyboard: [][]tgbotapi.KeyboardButton{
-				{tgbotapi.NewKeyboardButton("Пропустить"), tgbotapi.NewKeyboardButton("Главное меню")},
-			},
-			ResizeKeyboard: true,
-		}
-		if _, err := bot.Send(msg); err != nil {
-			log.Printf("Ошибка отправки сообщения: %v", err)
+		if !hasTime {
+			msg := tgbotapi.NewMessage(chatID, "Укажите также время начала, например «"+text+" в 15:00», или нажмите 'Весь день'.")
+			if _, err := bot.Send(msg); err != nil {
+				log.Printf("Ошибка отправки сообщения: %v", err)
+			}
+			return
 		}
+		event.DatetimeStart = startTime
+		event.TimeZone = loc.String()
+		event.IsAllDay = false
+		tempEvent[chatID] = event
+		confirmEventTime(bot, chatID, dateparse.Describe(startTime, true))
 
 	case "creating_event_all_day_date":
 		if text == "Главное меню" {
 			userSteps[chatID] = ""
 			return
 		}
-		layout := "02.01.2006"
-		allDayDate, err := time.Parse(layout, text)
+		loc := userLocation(chatID)
+		allDayDate, _, err := dateparse.Parse(text, time.Now().In(loc))
 		if err != nil {
-			msg := tgbotapi.NewMessage(chatID, "Неверный формат. Пожалуйста, введите дату в формате дд.мм.гггг.")
+			log.Printf("Ошибка парсинга даты '%s': %v", text, err)
+			msg := tgbotapi.NewMessage(chatID, "Не удалось распознать дату. Попробуйте, например, «25 декабря», «в субботу» или дд.мм.гггг.")
 			if _, err := bot.Send(msg); err != nil {
 				log.Printf("Ошибка отправки сообщения: %v", err)
 			}
 			return
 		}
+		// Для мероприятия на весь день время не учитывается
+		allDayDate = time.Date(allDayDate.Year(), allDayDate.Month(), allDayDate.Day(), 0, 0, 0, 0, loc)
 		event.DatetimeStart = allDayDate
+		event.TimeZone = loc.String()
 		event.IsAllDay = true
 		tempEvent[chatID] = event
-		userSteps[chatID] = "creating_event_duration"
-		log.Printf("Переход к состоянию: %s", userSteps[chatID])
-		msg := tgbotapi.NewMessage(chatID, "Введите продолжительность мероприятия (например, 1d2h) или нажмите 'Пропустить':")
-		msg.ReplyMarkup = tgbotapi.ReplyKeyboardMarkup{
-			Keyboard: [][]tgbotapi.KeyboardButton{
-				{tgbotapi.NewKeyboardButton("Пропустить"), tgbotapi.NewKeyboardButton("Главное меню")},
-			},
-			ResizeKeyboard: true,
+		confirmEventTime(bot, chatID, dateparse.Describe(allDayDate, false)+", весь день")
+
+	case "resolving_event_conflicts":
+		switch text {
+		case "Сохранить всё равно":
+			storeCreatedEvent(bot, chatID)
+		case "Выбрать другое время":
+			event.IsAllDay = false
+			tempEvent[chatID] = event
+			rescheduledEvent[chatID] = true
+			askEventTime(bot, chatID, "Введите новые дату и время начала или нажмите 'Весь день':")
+		case "Отмена":
+			delete(tempEvent, chatID)
+			delete(tempEventReminders, chatID)
+			delete(tempEventTemplate, chatID)
+			delete(rescheduledEvent, chatID)
+			delete(userSteps, chatID)
+			bot.Send(tgbotapi.NewMessage(chatID, "Создание мероприятия отменено."))
+			sendMainMenu(bot, chatID)
+		default:
+			msg := tgbotapi.NewMessage(chatID, "Пожалуйста, выберите один из вариантов на клавиатуре.")
+			if _, err := bot.Send(msg); err != nil {
+				log.Printf("Ошибка отправки сообщения: %v", err)
+			}
 		}
-		if _, err = bot.Send(msg); err != nil {
-			log.Printf("Ошибка отправки сообщения: %v", err)
+
+	case "confirming_event_time":
+		switch text {
+		case "Да":
+			// Остальные поля уже заполнены шаблоном или до выбора другого времени
+			if _, ok := tempEventTemplate[chatID]; ok || rescheduledEvent[chatID] {
+				delete(rescheduledEvent, chatID)
+				saveCreatedEvent(bot, chatID)
+				return
+			}
+			askEventDuration(bot, chatID)
+		case "Нет":
+			event.IsAllDay = false
+			tempEvent[chatID] = event
+			askEventTime(bot, chatID, "Введите дату и время начала ещё раз или нажмите 'Весь день':")
+		default:
+			msg := tgbotapi.NewMessage(chatID, "Пожалуйста, ответьте 'Да' или 'Нет'.")
+			if _, err := bot.Send(msg); err != nil {
+				log.Printf("Ошибка отправки сообщения: %v", err)
+			}
 		}
 
 	case "creating_event_duration":
@@ -790,29 +1027,244 @@ func handleEventCreation(bot *tgbotapi.BotAPI, chatID int64, text string) {
 			event.Duration = 0 // Если пользователь пропустил, устанавливаем продолжительность как 0
 		}
 
-		event.Status = "Запланировано"
+		tempEvent[chatID] = event
+		userSteps[chatID] = "creating_event_link"
+		log.Printf("Переход к состоянию: %s", userSteps[chatID])
+		msg := tgbotapi.NewMessage(chatID, "Вставьте ссылку на видеовстречу (Zoom, Meet, Телемост и т.п.) или нажмите 'Пропустить':")
+		msg.ReplyMarkup = tgbotapi.ReplyKeyboardMarkup{
+			Keyboard: [][]tgbotapi.KeyboardButton{
+				{tgbotapi.NewKeyboardButton("Пропустить"), tgbotapi.NewKeyboardButton("Главное меню")},
+			},
+			ResizeKeyboard: true,
+		}
+		if _, err := bot.Send(msg); err != nil {
+			log.Printf("Ошибка отправки сообщения: %v", err)
+		}
+
+	case "creating_event_link":
+		if text != "Пропустить" {
+			link, err := parseMeetingLink(text)
+			if err != nil {
+				log.Printf("Некорректная ссылка на встречу '%s': %v", text, err)
+				msg := tgbotapi.NewMessage(chatID, "Некорректная ссылка: "+err.Error()+
+					". Пример: https://meet.google.com/abc-defg-hij")
+				if _, err = bot.Send(msg); err != nil {
+					log.Printf("Ошибка отправки сообщения: %v", err)
+				}
+				return
+			}
+			event.LinkToVideo = link
+		} else {
+			event.LinkToVideo = ""
+		}
+
+		tempEvent[chatID] = event
+		userSteps[chatID] = "creating_event_location"
+		log.Printf("Переход к состоянию: %s", userSteps[chatID])
+		msg := tgbotapi.NewMessage(chatID, "Где пройдёт мероприятие? Отправьте геопозицию или точку на карте "+
+			"через 📎, введите адрес текстом или нажмите 'Пропустить':")
+		msg.ReplyMarkup = locationKeyboard("Пропустить")
+		if _, err := bot.Send(msg); err != nil {
+			log.Printf("Ошибка отправки сообщения: %v", err)
+		}
 
-		// Сохраняем событие в базу данных
-		if err := db.DB.Create(&event).Error; err != nil {
-			log.Println("Ошибка сохранения события:", err)
-			msg := tgbotapi.NewMessage(chatID, "Ошибка при сохранении события.")
-			if _, err := bot.Send(msg); err != nil {
+	case "creating_event_location":
+		// Геопозиция и точка на карте обрабатываются в handleLocationMessage
+		if text != "Пропустить" {
+			location, err := parseLocationText(text)
+			if err != nil {
+				msg := tgbotapi.NewMessage(chatID, "Некорректный адрес: "+err.Error())
+				if _, err = bot.Send(msg); err != nil {
+					log.Printf("Ошибка отправки сообщения: %v", err)
+				}
+				return
+			}
+			event.EventLocation = location
+		} else {
+			event.EventLocation = gorm_models2.EventLocation{}
+		}
+		tempEvent[chatID] = event
+		askEventRecurrence(bot, chatID)
+
+	case "creating_event_recurrence":
+		rule, err := parseRecurrenceInput(text)
+		if err != nil {
+			log.Printf("Ошибка парсинга правила повторения: %v", err)
+			msg := tgbotapi.NewMessage(chatID, "Не удалось разобрать правило повторения: "+err.Error())
+			if _, err = bot.Send(msg); err != nil {
 				log.Printf("Ошибка отправки сообщения: %v", err)
 			}
 			return
 		}
+		setEventRule(&event, rule)
+		tempEvent[chatID] = event
+		userSteps[chatID] = "creating_event_reminders"
+		log.Printf("Переход к состоянию: %s", userSteps[chatID])
+		msg := tgbotapi.NewMessage(chatID, "Когда напомнить о мероприятии? Введите интервалы через запятую "+
+			"(например, 1d, 1h, 10m) или нажмите 'По умолчанию', чтобы каждый участник получил напоминание по своим настройкам:")
+		msg.ReplyMarkup = tgbotapi.ReplyKeyboardMarkup{
+			Keyboard: [][]tgbotapi.KeyboardButton{
+				{tgbotapi.NewKeyboardButton("По умолчанию"), tgbotapi.NewKeyboardButton("Главное меню")},
+			},
+			ResizeKeyboard: true,
+		}
+		if _, err = bot.Send(msg); err != nil {
+			log.Printf("Ошибка отправки сообщения: %v", err)
+		}
+
+	case "creating_event_reminders":
+		if text != "По умолчанию" {
+			offsets, err := parseReminderOffsets(text)
+			if err != nil {
+				msg := tgbotapi.NewMessage(chatID, "Неверный формат интервалов: "+err.Error()+
+					"\nИспользуйте формат 1d2h3m, например: 1d, 1h, 10m.")
+				if _, err = bot.Send(msg); err != nil {
+					log.Printf("Ошибка отправки сообщения: %v", err)
+				}
+				return
+			}
+			tempEventReminders[chatID] = formatReminderOffsets(offsets)
+		} else {
+			delete(tempEventReminders, chatID)
+		}
+		saveCreatedEvent(bot, chatID)
+	}
+}
+
+// askEventTime переводит мастер создания к вводу даты и времени начала.
+// Дату и время можно ввести текстом или выбрать в календаре.
+func askEventTime(bot *tgbotapi.BotAPI, chatID int64, text string) {
+	userSteps[chatID] = "creating_event_time"
+	log.Printf("Переход к состоянию: %s", userSteps[chatID])
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyMarkup = tgbotapi.ReplyKeyboardMarkup{
+		Keyboard: [][]tgbotapi.KeyboardButton{
+			{tgbotapi.NewKeyboardButton("Весь день"), tgbotapi.NewKeyboardButton("Главное меню")},
+		},
+		ResizeKeyboard: true,
+	}
+	if _, err := bot.Send(msg); err != nil {
+		log.Printf("Ошибка отправки сообщения: %v", err)
+	}
+	sendDatePicker(bot, chatID)
+}
+
+// askEventRecurrence переводит мастер создания к выбору правила повторения
+func askEventRecurrence(bot *tgbotapi.BotAPI, chatID int64) {
+	userSteps[chatID] = "creating_event_recurrence"
+	log.Printf("Переход к состоянию: %s", userSteps[chatID])
+	msg := tgbotapi.NewMessage(chatID, "Повторять мероприятие? Выберите вариант или введите правило, "+
+		"например FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH;COUNT=10 (также поддерживается UNTIL=ГГГГММДД):")
+	msg.ReplyMarkup = recurrenceKeyboard()
+	if _, err := bot.Send(msg); err != nil {
+		log.Printf("Ошибка отправки сообщения: %v", err)
+	}
+}
+
+// confirmEventTime показывает, как бот понял введённую дату, и просит подтвердить её
+func confirmEventTime(bot *tgbotapi.BotAPI, chatID int64, description string) {
+	confirmParsedTime(bot, chatID, "confirming_event_time", description)
+}
+
+// confirmParsedTime переводит пользователя к шагу step и просит подтвердить распознанную дату
+func confirmParsedTime(bot *tgbotapi.BotAPI, chatID int64, step string, description string) {
+	userSteps[chatID] = step
+	log.Printf("Переход к состоянию: %s", userSteps[chatID])
+	msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("Я понял так: %s. Всё верно?", description))
+	msg.ReplyMarkup = tgbotapi.ReplyKeyboardMarkup{
+		Keyboard: [][]tgbotapi.KeyboardButton{
+			{tgbotapi.NewKeyboardButton("Да"), tgbotapi.NewKeyboardButton("Нет")},
+			{tgbotapi.NewKeyboardButton("Главное меню")},
+		},
+		ResizeKeyboard: true,
+	}
+	if _, err := bot.Send(msg); err != nil {
+		log.Printf("Ошибка отправки сообщения: %v", err)
+	}
+}
+
+// askEventDuration переводит мастер создания к вводу продолжительности
+func askEventDuration(bot *tgbotapi.BotAPI, chatID int64) {
+	userSteps[chatID] = "creating_event_duration"
+	log.Printf("Переход к состоянию: %s", userSteps[chatID])
+	msg := tgbotapi.NewMessage(chatID, "Введите продолжительность мероприятия (например, 1d2h) или нажмите 'Пропустить':")
+	msg.ReplyMarkup = tgbotapi.ReplyKeyboardMarkup{
+		Keyboard: [][]tgbotapi.KeyboardButton{
+			{tgbotapi.NewKeyboardButton("Пропустить"), tgbotapi.NewKeyboardButton("Главное меню")},
+		},
+		ResizeKeyboard: true,
+	}
+	if _, err := bot.Send(msg); err != nil {
+		log.Printf("Ошибка отправки сообщения: %v", err)
+	}
+}
+
+// saveCreatedEvent сохраняет мероприятие, собранное мастером создания, если оно ни с чем
+// не пересекается, иначе спрашивает пользователя, что делать
+func saveCreatedEvent(bot *tgbotapi.BotAPI, chatID int64) {
+	event := tempEvent[chatID]
+	if event.RecurFreq != "" {
+		// Мероприятие начинается с первого повторения, чтобы карточка не показывала дату, которой нет в расписании
+		first, ok := firstOccurrenceStart(event)
+		if !ok {
+			event.IsAllDay = false
+			tempEvent[chatID] = event
+			rescheduledEvent[chatID] = true
+			askEventTime(bot, chatID, "По выбранному правилу мероприятие не повторится ни разу. "+
+				"Введите другие дату и время начала или нажмите 'Весь день':")
+			return
+		}
+		if !first.Equal(event.DatetimeStart) {
+			event.DatetimeStart = first
+			tempEvent[chatID] = event
+			msg := tgbotapi.NewMessage(chatID, "Первое повторение по правилу — "+
+				dateparse.Describe(first, !event.IsAllDay)+". Мероприятие начнётся с этой даты.")
+			if _, err := bot.Send(msg); err != nil {
+				log.Printf("Ошибка отправки сообщения: %v", err)
+			}
+		}
+	}
+	if checkEventConflicts(bot, chatID, tempEvent[chatID]) {
+		return
+	}
+	storeCreatedEvent(bot, chatID)
+}
 
-		delete(tempEvent, chatID) // Удаляем временные данные
-		delete(userSteps, chatID) // Сбрасываем шаги
+// storeCreatedEvent сохраняет мероприятие, собранное мастером создания, без проверки пересечений
+func storeCreatedEvent(bot *tgbotapi.BotAPI, chatID int64) {
+	event := tempEvent[chatID]
+	event.Status = gorm_models2.EventStatusPlanned
 
-		log.Println("Мероприятие успешно создано.")
-		msg := tgbotapi.NewMessage(chatID, "Мероприятие успешно создано!")
+	// Сохраняем событие в базу данных
+	if err := db.DB.Create(&event).Error; err != nil {
+		log.Println("Ошибка сохранения события:", err)
+		msg := tgbotapi.NewMessage(chatID, "Ошибка при сохранении события.")
 		if _, err := bot.Send(msg); err != nil {
 			log.Printf("Ошибка отправки сообщения: %v", err)
 		}
+		return
+	}
 
-		sendMainMenu(bot, chatID) // Возвращаем пользователя в главное меню
+	// Сохраняем интервалы напоминаний, заданные для мероприятия
+	if offsets, ok := tempEventReminders[chatID]; ok {
+		if err := provider.SetEventReminderOffsets(context.Background(), event.IDEvent, offsets); err != nil {
+			log.Printf("Ошибка сохранения напоминаний мероприятия ID %d: %v", event.IDEvent, err)
+		}
+		delete(tempEventReminders, chatID)
 	}
+
+	delete(tempEvent, chatID) // Удаляем временные данные
+	delete(tempEventTemplate, chatID)
+	delete(rescheduledEvent, chatID)
+	delete(userSteps, chatID) // Сбрасываем шаги
+
+	log.Println("Мероприятие успешно создано.")
+	msg := tgbotapi.NewMessage(chatID, "Мероприятие успешно создано!")
+	if _, err := bot.Send(msg); err != nil {
+		log.Printf("Ошибка отправки сообщения: %v", err)
+	}
+
+	sendMainMenu(bot, chatID) // Возвращаем пользователя в главное меню
 }
 
 // ---- Функционал создания группы ----
@@ -870,6 +1322,12 @@ func handleGroupCreation(bot *tgbotapi.BotAPI, chatID int64, text string) {
 			bot.Send(tgbotapi.NewMessage(chatID, "Ошибка при создании группы."))
 			return
 		}
+		if err := provider.CreateDefaultCategories(context.Background(), newGroup.IDGroup); err != nil {
+			log.Printf("Ошибка создания категорий группы %d: %v", newGroup.IDGroup, err)
+		}
+		if err := provider.CreateDefaultTaskColumns(context.Background(), newGroup.IDGroup); err != nil {
+			log.Printf("Ошибка создания колонок доски группы %d: %v", newGroup.IDGroup, err)
+		}
 
 		// Добавляем администратора в таблицу `Membership`
 		adminMembership := gorm_models2.Membership{
@@ -1015,15 +1473,7 @@ func handleCallbackQuery(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery)
 			Category: "Группа " + group.GroupName,
 		}
 
-		msg := tgbotapi.NewMessage(chatID, "Выберите категорию мероприятия:")
-		msg.ReplyMarkup = tgbotapi.ReplyKeyboardMarkup{
-			Keyboard: [][]tgbotapi.KeyboardButton{
-				{tgbotapi.NewKeyboardButton("Личное"), tgbotapi.NewKeyboardButton("Семья"), tgbotapi.NewKeyboardButton("Работа")},
-				{tgbotapi.NewKeyboardButton("Главное меню")},
-			},
-			ResizeKeyboard: true,
-		}
-		bot.Send(msg)
+		sendCategoryPrompt(bot, chatID, groupID64, "Выберите категорию мероприятия:")
 
 		// Уведомляем Telegram о завершении обработки callback
 		bot.Request(tgbotapi.NewCallback(callback.ID, "Группа выбрана!"))
@@ -1064,24 +1514,14 @@ func handleCallbackQuery(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery)
 
 	// Обработка подтверждения удаления
 	if strings.HasPrefix(data, "confirm_delete_") {
-		eventID, err := strconv.Atoi(strings.TrimPrefix(data, "confirm_delete_"))
+		eventID, err := strconv.ParseInt(strings.TrimPrefix(data, "confirm_delete_"), 10, 64)
 		if err != nil {
 			log.Printf("Ошибка преобразования ID мероприятия: %v", err)
 			bot.Request(tgbotapi.NewCallback(callback.ID, "Некорректный ID мероприятия."))
 			return
 		}
 
-		err = db.DB.Delete(&gorm_models2.Event{}, eventID).Error
-		if err != nil {
-			log.Printf("Ошибка удаления мероприятия: %v", err)
-			bot.Request(tgbotapi.NewCallback(callback.ID, "Не удалось удалить мероприятие."))
-			return
-		}
-
-		bot.Request(tgbotapi.NewCallback(callback.ID, "Мероприятие успешно удалено."))
-		msg := tgbotapi.NewMessage(chatID, "Мероприятие успешно удалено.")
-		bot.Send(msg)
-		viewMyEvents(bot, chatID)
+		trashEvent(bot, callback, eventID)
 		return
 	}
 
@@ -1222,6 +1662,139 @@ func handleCallbackQuery(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery)
 		return
 	}
 
+	// Редактирование мероприятия
+	if strings.HasPrefix(data, "edit_event_") {
+		eventID, err := strconv.ParseInt(strings.TrimPrefix(data, "edit_event_"), 10, 64)
+		if err != nil {
+			log.Printf("Ошибка преобразования ID мероприятия: %v", err)
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Некорректный ID мероприятия."))
+			return
+		}
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		startEditEvent(bot, chatID, eventID)
+		return
+	}
+
+	if strings.HasPrefix(data, "edit_") {
+		handleEditCallback(bot, callback)
+		return
+	}
+
+	// Настройка интервалов напоминаний
+	if strings.HasPrefix(data, "remind_pref_") {
+		handleReminderSettingsCallback(bot, callback)
+		return
+	}
+
+	// Управление повторениями регулярного мероприятия
+	if strings.HasPrefix(data, "occurrences_") || strings.HasPrefix(data, "skip_occ_") || strings.HasPrefix(data, "move_occ_") {
+		handleOccurrenceCallback(bot, callback)
+		return
+	}
+
+	// Управление категориями группы
+	if strings.HasPrefix(data, "cat_") {
+		handleCategoryCallback(bot, callback)
+		return
+	}
+
+	// Выбор часового пояса
+	if strings.HasPrefix(data, "tz_") {
+		handleTimeZoneCallback(bot, callback)
+		return
+	}
+
+	// Карточка мероприятия и ответы участников
+	if strings.HasPrefix(data, "event_card_") || strings.HasPrefix(data, "rsvp_") {
+		handleRSVPCallback(bot, callback)
+		return
+	}
+
+	// Чек-лист мероприятия
+	if strings.HasPrefix(data, "checklist_") {
+		handleChecklistCallback(bot, callback)
+		return
+	}
+
+	// Задачи
+	if strings.HasPrefix(data, "task_") {
+		handleTaskCallback(bot, callback)
+		return
+	}
+
+	// Доска задач и её колонки
+	if strings.HasPrefix(data, "board_") {
+		handleBoardCallback(bot, callback)
+		return
+	}
+	if strings.HasPrefix(data, "tcol_") {
+		handleTaskColumnCallback(bot, callback)
+		return
+	}
+
+	// Ручная смена статуса мероприятия
+	if strings.HasPrefix(data, "evst_") {
+		handleEventStatusCallback(bot, callback)
+		return
+	}
+
+	// Шаблоны мероприятий
+	if strings.HasPrefix(data, "tpl_") {
+		handleTemplateCallback(bot, callback)
+		return
+	}
+
+	// Место мероприятия на карте
+	if strings.HasPrefix(data, "venue_") {
+		handleVenueCallback(bot, callback)
+		return
+	}
+
+	// Вложения мероприятия
+	if strings.HasPrefix(data, "attach_") {
+		handleAttachmentCallback(bot, callback)
+		return
+	}
+
+	// Комментарии к мероприятию
+	if strings.HasPrefix(data, "comment_") {
+		handleCommentCallback(bot, callback)
+		return
+	}
+
+	// Календарь и выбор времени
+	if strings.HasPrefix(data, "cal_") || strings.HasPrefix(data, "tp_") {
+		handlePickerCallback(bot, callback)
+		return
+	}
+
+	if strings.HasPrefix(data, "events_page_") || strings.HasPrefix(data, "groups_page_") {
+		handlePageCallback(bot, callback)
+		return
+	}
+
+	if strings.HasPrefix(data, "digest_") {
+		handleDigestCallback(bot, callback)
+		return
+	}
+
+	if strings.HasPrefix(data, "trash_") {
+		handleTrashCallback(bot, callback)
+		return
+	}
+
+	// Расписание на день, неделю и месяц
+	if strings.HasPrefix(data, "agenda_") {
+		handleAgendaCallback(bot, callback)
+		return
+	}
+
+	// Поиск мероприятий
+	if strings.HasPrefix(data, "find_") {
+		handleSearchCallback(bot, callback)
+		return
+	}
+
 	// Если callback не распознан
 	bot.Request(tgbotapi.NewCallback(callback.ID, "Неизвестное действие"))
 }
@@ -1276,44 +1849,57 @@ func UpdateEventStatuses(db *gorm.DB) {
 	}
 
 	// Получаем текущее время
-	localTime := time.Now()
+	currentTime := time.Now()
 
-	// Извлекаем компоненты локального времени
-	year, month, day := localTime.Date()
-	hour, min, sec := localTime.Clock()
-
-	// Создаем новое время с часовым поясом UTC, но используя компоненты локального времени
-	currentTime := time.Date(year, month, day, hour, min, sec, localTime.Nanosecond(), time.UTC)
+	// Исключения нужны для вычисления статуса повторяющихся мероприятий
+	exceptions := loadExceptions(events)
 
 	for _, event := range events {
 		previousStatus := event.Status
+		previousManual := event.StatusManual
 
-		startTime := event.DatetimeStart.UTC()
-		var endTime time.Time
-		if event.Duration > 0 {
-			endTime = startTime.Add(event.Duration)
-		} else {
-			endTime = startTime // Если продолжительность равна 0, конец совпадает с началом
+		if event.StatusManual {
+			// Выставленный вручную статус не пересчитывается,
+			// кроме перенесённого мероприятия, новое время которого уже наступило
+			if event.Status != gorm_models2.EventStatusPostponed || currentTime.Before(event.DatetimeStart) {
+				continue
+			}
+			event.StatusManual = false
 		}
 
-		log.Printf("Проверяем мероприятие ID: %d, StartTime: %v, EndTime: %v, CurrentTime: %v", event.IDEvent, startTime, endTime, currentTime)
+		if event.RecurFreq != "" {
+			event.Status = recurringEventStatus(event, exceptions[event.IDEvent], currentTime)
+		} else {
+			startTime := event.DatetimeStart
+			var endTime time.Time
+			if event.Duration > 0 {
+				endTime = startTime.Add(event.Duration)
+			} else if event.IsAllDay {
+				// Мероприятие на весь день длится до полуночи в часовом поясе мероприятия
+				endTime = startTime.In(eventLocation(event)).AddDate(0, 0, 1)
+			} else {
+				endTime = startTime // Если продолжительность равна 0, конец совпадает с началом
+			}
+
+			log.Printf("Проверяем мероприятие ID: %d, StartTime: %v, EndTime: %v, CurrentTime: %v", event.IDEvent, startTime, endTime, currentTime)
 
-		// Логика определения статуса
-		if currentTime.Before(startTime) {
-			event.Status = "Запланировано"
-		} else if currentTime.After(endTime) {
-			event.Status = "Завершено"
-		} else if currentTime.After(startTime) && currentTime.Before(endTime) {
-			event.Status = "В процессе"
+			// Логика определения статуса
+			if currentTime.Before(startTime) {
+				event.Status = "Запланировано"
+			} else if currentTime.After(endTime) {
+				event.Status = "Завершено"
+			} else if currentTime.After(startTime) && currentTime.Before(endTime) {
+				event.Status = "В процессе"
+			}
 		}
 
 		log.Printf("Статус мероприятия ID: %d изменился с '%s' на '%s'", event.IDEvent, previousStatus, event.Status)
 
 		// Обновляем статус в базе, если он изменился
-		if previousStatus != event.Status {
+		if previousStatus != event.Status || previousManual != event.StatusManual {
 			err := db.Model(&gorm_models2.Event{}).
 				Where("id_event = ?", event.IDEvent).
-				Update("status", event.Status).Error
+				Updates(map[string]interface{}{"status": event.Status, "status_manual": event.StatusManual}).Error
 			if err != nil {
 				log.Printf("Ошибка обновления статуса мероприятия ID %d: %v", event.IDEvent, err)
 			} else {
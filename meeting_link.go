@@ -0,0 +1,41 @@
+package main
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	gorm_models2 "aliorToDoBot/src/db/gorm_models"
+)
+
+// ---- Функционал ссылок на видеовстречи ----
+
+// parseMeetingLink проверяет ссылку на видеовстречу: нужен полный адрес http или https
+func parseMeetingLink(text string) (string, error) {
+	text = strings.TrimSpace(text)
+	if !strings.Contains(text, "://") {
+		text = "https://" + text
+	}
+
+	link, err := url.ParseRequestURI(text)
+	if err != nil {
+		return "", errors.New("не похоже на ссылку")
+	}
+	if link.Scheme != "http" && link.Scheme != "https" {
+		return "", errors.New("поддерживаются только ссылки http и https")
+	}
+	if link.Host == "" || !strings.Contains(link.Hostname(), ".") {
+		return "", errors.New("в ссылке не указан адрес сайта")
+	}
+	return link.String(), nil
+}
+
+// meetingLinkButton возвращает кнопку для подключения к видеовстрече мероприятия
+func meetingLinkButton(event gorm_models2.Event) (tgbotapi.InlineKeyboardButton, bool) {
+	if event.LinkToVideo == "" {
+		return tgbotapi.InlineKeyboardButton{}, false
+	}
+	return tgbotapi.NewInlineKeyboardButtonURL("🎥 Подключиться", event.LinkToVideo), true
+}
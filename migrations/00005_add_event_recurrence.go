@@ -0,0 +1,56 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddEventRecurrence, downAddEventRecurrence)
+}
+
+func upAddEventRecurrence(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	_, err := tx.ExecContext(ctx, `
+		ALTER TABLE todo_event
+    		ADD COLUMN recur_freq text NOT NULL DEFAULT '' CHECK (recur_freq IN ('', 'daily', 'weekly', 'monthly')),
+    		ADD COLUMN recur_interval integer NOT NULL DEFAULT 1,
+    		ADD COLUMN recur_weekdays text NOT NULL DEFAULT '',
+    		ADD COLUMN recur_until TIMESTAMP,
+    		ADD COLUMN recur_count integer NOT NULL DEFAULT 0;
+
+		CREATE TABLE todo_event_exception(
+    		id_exception SERIAL PRIMARY KEY,
+    		id_event integer NOT NULL,
+    		occurrence_start TIMESTAMP NOT NULL,
+    		is_skipped boolean NOT NULL DEFAULT false,
+    		new_start TIMESTAMP,
+    		new_duration bigint,
+    		UNIQUE (id_event, occurrence_start),
+    		FOREIGN KEY (id_event) REFERENCES todo_event(id_event) ON DELETE CASCADE
+		);
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downAddEventRecurrence(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	_, err := tx.ExecContext(ctx, `
+		DROP TABLE todo_event_exception;
+		ALTER TABLE todo_event
+    		DROP COLUMN recur_freq,
+    		DROP COLUMN recur_interval,
+    		DROP COLUMN recur_weekdays,
+    		DROP COLUMN recur_until,
+    		DROP COLUMN recur_count;
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upNewSentReminderTable, downNewSentReminderTable)
+}
+
+func upNewSentReminderTable(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE todo_sent_reminder(
+    		id_event integer NOT NULL,
+    		occurrence_start TIMESTAMP NOT NULL,
+    		id_user text NOT NULL,
+    		sent_at TIMESTAMP NOT NULL DEFAULT now(),
+    		UNIQUE (id_event, occurrence_start, id_user),
+    		FOREIGN KEY (id_event) REFERENCES todo_event(id_event) ON DELETE CASCADE,
+    		FOREIGN KEY (id_user) REFERENCES todo_user(id_user)
+		);
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downNewSentReminderTable(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	_, err := tx.ExecContext(ctx, `
+		DROP TABLE todo_sent_reminder;
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upNewReminderPreferenceTable, downNewReminderPreferenceTable)
+}
+
+func upNewReminderPreferenceTable(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE todo_reminder_preference(
+    		id_preference SERIAL PRIMARY KEY,
+    		id_user text,
+    		category text NOT NULL DEFAULT '',
+    		id_event integer,
+    		offsets text NOT NULL,
+    		FOREIGN KEY (id_user) REFERENCES todo_user(id_user),
+    		FOREIGN KEY (id_event) REFERENCES todo_event(id_event) ON DELETE CASCADE
+		);
+
+		ALTER TABLE todo_sent_reminder
+    		ADD COLUMN reminder_offset bigint NOT NULL DEFAULT 0,
+    		DROP CONSTRAINT todo_sent_reminder_id_event_occurrence_start_id_user_key,
+    		ADD CONSTRAINT todo_sent_reminder_offset_key UNIQUE (id_event, occurrence_start, id_user, reminder_offset);
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downNewReminderPreferenceTable(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	// Без смещения напоминания об одном повторении совпадут: оставляем по одной записи
+	_, err := tx.ExecContext(ctx, `
+		DELETE FROM todo_sent_reminder a
+		USING todo_sent_reminder b
+		WHERE b.id_event = a.id_event
+		  AND b.occurrence_start = a.occurrence_start
+		  AND b.id_user = a.id_user
+		  AND b.ctid < a.ctid;
+
+		ALTER TABLE todo_sent_reminder
+    		DROP CONSTRAINT todo_sent_reminder_offset_key,
+    		DROP COLUMN reminder_offset,
+    		ADD UNIQUE (id_event, occurrence_start, id_user);
+
+		DROP TABLE todo_reminder_preference;
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
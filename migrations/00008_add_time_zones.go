@@ -0,0 +1,70 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddTimeZones, downAddTimeZones)
+}
+
+func upAddTimeZones(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	// Раньше время хранилось как московское без часового пояса.
+	// Заодно исправляется опечатка в названии столбца datatime_start из 00003.
+	_, err := tx.ExecContext(ctx, `
+		ALTER TABLE todo_user
+    		ADD COLUMN time_zone text NOT NULL DEFAULT 'Europe/Moscow';
+
+		ALTER TABLE todo_event
+    		RENAME COLUMN datatime_start TO datetime_start;
+
+		ALTER TABLE todo_event
+    		ADD COLUMN time_zone text NOT NULL DEFAULT 'Europe/Moscow',
+    		ALTER COLUMN datetime_start TYPE TIMESTAMPTZ USING datetime_start AT TIME ZONE 'Europe/Moscow',
+    		ALTER COLUMN recur_until TYPE TIMESTAMPTZ USING recur_until AT TIME ZONE 'Europe/Moscow';
+
+		ALTER TABLE todo_event_exception
+    		ALTER COLUMN occurrence_start TYPE TIMESTAMPTZ USING occurrence_start AT TIME ZONE 'Europe/Moscow',
+    		ALTER COLUMN new_start TYPE TIMESTAMPTZ USING new_start AT TIME ZONE 'Europe/Moscow';
+
+		ALTER TABLE todo_sent_reminder
+    		ALTER COLUMN occurrence_start TYPE TIMESTAMPTZ USING occurrence_start AT TIME ZONE 'Europe/Moscow',
+    		ALTER COLUMN sent_at TYPE TIMESTAMPTZ USING sent_at AT TIME ZONE 'Europe/Moscow';
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downAddTimeZones(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	_, err := tx.ExecContext(ctx, `
+		ALTER TABLE todo_sent_reminder
+    		ALTER COLUMN occurrence_start TYPE TIMESTAMP USING occurrence_start AT TIME ZONE 'Europe/Moscow',
+    		ALTER COLUMN sent_at TYPE TIMESTAMP USING sent_at AT TIME ZONE 'Europe/Moscow';
+
+		ALTER TABLE todo_event_exception
+    		ALTER COLUMN occurrence_start TYPE TIMESTAMP USING occurrence_start AT TIME ZONE 'Europe/Moscow',
+    		ALTER COLUMN new_start TYPE TIMESTAMP USING new_start AT TIME ZONE 'Europe/Moscow';
+
+		ALTER TABLE todo_event
+    		DROP COLUMN time_zone,
+    		ALTER COLUMN datetime_start TYPE TIMESTAMP USING datetime_start AT TIME ZONE 'Europe/Moscow',
+    		ALTER COLUMN recur_until TYPE TIMESTAMP USING recur_until AT TIME ZONE 'Europe/Moscow';
+
+		ALTER TABLE todo_event
+    		RENAME COLUMN datetime_start TO datatime_start;
+
+		ALTER TABLE todo_user
+    		DROP COLUMN time_zone;
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upNewCategoryTable, downNewCategoryTable)
+}
+
+func upNewCategoryTable(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE todo_category(
+    		id_category SERIAL PRIMARY KEY,
+    		id_group integer NOT NULL,
+    		name text NOT NULL,
+    		emoji text NOT NULL DEFAULT '',
+    		UNIQUE (id_group, name),
+    		FOREIGN KEY (id_group) REFERENCES todo_group(id_group) ON DELETE CASCADE
+		);
+
+		INSERT INTO todo_category (id_group, name, emoji)
+		SELECT g.id_group, c.name, c.emoji
+		FROM todo_group g
+		CROSS JOIN (VALUES ('Личное', '🙂'), ('Семья', '👨‍👩‍👧'), ('Работа', '💼')) AS c(name, emoji);
+
+		ALTER TABLE todo_event
+    		ALTER COLUMN category TYPE text USING category::text;
+		DROP TYPE IF EXISTS event_category;
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downNewCategoryTable(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	_, err := tx.ExecContext(ctx, `
+		UPDATE todo_event SET category = 'Личное' WHERE category NOT IN ('Личное', 'Семья', 'Работа');
+		CREATE TYPE event_category AS ENUM ('Личное', 'Семья', 'Работа');
+		ALTER TABLE todo_event
+    		ALTER COLUMN category TYPE event_category USING category::event_category;
+		DROP TABLE todo_category;
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
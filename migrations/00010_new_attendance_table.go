@@ -0,0 +1,42 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upNewAttendanceTable, downNewAttendanceTable)
+}
+
+func upNewAttendanceTable(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE todo_attendance(
+    		id_event integer NOT NULL,
+    		id_user text NOT NULL,
+    		status text NOT NULL CHECK (status IN ('going', 'not_going', 'maybe')),
+    		updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    		UNIQUE (id_event, id_user),
+    		FOREIGN KEY (id_event) REFERENCES todo_event(id_event) ON DELETE CASCADE,
+    		FOREIGN KEY (id_user) REFERENCES todo_user(id_user)
+		);
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downNewAttendanceTable(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	_, err := tx.ExecContext(ctx, `
+		DROP TABLE todo_attendance;
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
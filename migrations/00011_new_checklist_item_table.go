@@ -0,0 +1,45 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upNewChecklistItemTable, downNewChecklistItemTable)
+}
+
+func upNewChecklistItemTable(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE todo_checklist_item(
+    		id_item SERIAL PRIMARY KEY,
+    		id_event integer NOT NULL,
+    		text text NOT NULL,
+    		is_done boolean NOT NULL DEFAULT false,
+    		id_checked_by text,
+    		checked_at TIMESTAMPTZ,
+    		FOREIGN KEY (id_event) REFERENCES todo_event(id_event) ON DELETE CASCADE,
+    		FOREIGN KEY (id_checked_by) REFERENCES todo_user(id_user)
+		);
+
+		CREATE INDEX idx_todo_checklist_item_id_event ON todo_checklist_item(id_event);
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downNewChecklistItemTable(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	_, err := tx.ExecContext(ctx, `
+		DROP TABLE todo_checklist_item;
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upNewTaskTable, downNewTaskTable)
+}
+
+func upNewTaskTable(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE todo_task(
+    		id_task SERIAL PRIMARY KEY,
+    		id_group integer NOT NULL,
+    		title text NOT NULL,
+    		priority text NOT NULL DEFAULT 'normal' CHECK (priority IN ('high', 'normal', 'low')),
+    		deadline TIMESTAMPTZ,
+    		is_done boolean NOT NULL DEFAULT false,
+    		done_at TIMESTAMPTZ,
+    		id_creator text NOT NULL,
+    		created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    		FOREIGN KEY (id_group) REFERENCES todo_group(id_group) ON DELETE CASCADE,
+    		FOREIGN KEY (id_creator) REFERENCES todo_user(id_user)
+		);
+
+		CREATE INDEX idx_todo_task_id_group ON todo_task(id_group);
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downNewTaskTable(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	_, err := tx.ExecContext(ctx, `
+		DROP TABLE todo_task;
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
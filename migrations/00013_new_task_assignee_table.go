@@ -0,0 +1,40 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upNewTaskAssigneeTable, downNewTaskAssigneeTable)
+}
+
+func upNewTaskAssigneeTable(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE todo_task_assignee(
+    		id_task integer NOT NULL,
+    		id_user text NOT NULL,
+    		UNIQUE (id_task, id_user),
+    		FOREIGN KEY (id_task) REFERENCES todo_task(id_task) ON DELETE CASCADE,
+    		FOREIGN KEY (id_user) REFERENCES todo_user(id_user)
+		);
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downNewTaskAssigneeTable(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	_, err := tx.ExecContext(ctx, `
+		DROP TABLE todo_task_assignee;
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upNewTaskColumnTable, downNewTaskColumnTable)
+}
+
+func upNewTaskColumnTable(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE todo_task_column(
+    		id_column SERIAL PRIMARY KEY,
+    		id_group integer NOT NULL,
+    		name text NOT NULL,
+    		position integer NOT NULL DEFAULT 0,
+    		is_done boolean NOT NULL DEFAULT false,
+    		UNIQUE (id_group, name),
+    		FOREIGN KEY (id_group) REFERENCES todo_group(id_group) ON DELETE CASCADE
+		);
+
+		INSERT INTO todo_task_column (id_group, name, position, is_done)
+		SELECT g.id_group, c.name, c.position, c.is_done
+		FROM todo_group g
+		CROSS JOIN (VALUES ('К выполнению', 1, false), ('В работе', 2, false), ('Готово', 3, true)) AS c(name, position, is_done);
+
+		ALTER TABLE todo_task
+    		ADD COLUMN id_column integer REFERENCES todo_task_column(id_column) ON DELETE SET NULL;
+
+		UPDATE todo_task t
+		SET id_column = c.id_column
+		FROM todo_task_column c
+		WHERE c.id_group = t.id_group
+		  AND c.is_done = t.is_done
+		  AND c.position = CASE WHEN t.is_done THEN 3 ELSE 1 END;
+
+		CREATE INDEX idx_todo_task_id_column ON todo_task(id_column);
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downNewTaskColumnTable(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	_, err := tx.ExecContext(ctx, `
+		ALTER TABLE todo_task DROP COLUMN id_column;
+		DROP TABLE todo_task_column;
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddEventManualStatus, downAddEventManualStatus)
+}
+
+func upAddEventManualStatus(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	_, err := tx.ExecContext(ctx, `
+		ALTER TABLE todo_event
+    		ALTER COLUMN status TYPE text USING status::text,
+    		ADD COLUMN status_manual boolean NOT NULL DEFAULT false,
+    		ADD CONSTRAINT todo_event_status_check
+    		    CHECK (status IN ('Запланировано', 'В процессе', 'Завершено', 'Отменено', 'Перенесено'));
+		DROP TYPE IF EXISTS event_status;
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downAddEventManualStatus(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	_, err := tx.ExecContext(ctx, `
+		UPDATE todo_event SET status = 'Запланировано' WHERE status IN ('Отменено', 'Перенесено');
+		CREATE TYPE event_status AS ENUM ('Запланировано', 'В процессе', 'Завершено');
+		ALTER TABLE todo_event
+    		DROP CONSTRAINT todo_event_status_check,
+    		ALTER COLUMN status TYPE event_status USING status::event_status,
+    		DROP COLUMN status_manual;
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upNewEventCommentTable, downNewEventCommentTable)
+}
+
+func upNewEventCommentTable(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE todo_event_comment(
+    		id_comment SERIAL PRIMARY KEY,
+    		id_event integer NOT NULL,
+    		id_user text NOT NULL,
+    		text text NOT NULL,
+    		created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    		FOREIGN KEY (id_event) REFERENCES todo_event(id_event) ON DELETE CASCADE,
+    		FOREIGN KEY (id_user) REFERENCES todo_user(id_user)
+		);
+
+		CREATE INDEX idx_todo_event_comment_id_event ON todo_event_comment(id_event);
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downNewEventCommentTable(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	_, err := tx.ExecContext(ctx, `
+		DROP TABLE todo_event_comment;
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
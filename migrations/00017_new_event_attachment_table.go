@@ -0,0 +1,49 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upNewEventAttachmentTable, downNewEventAttachmentTable)
+}
+
+func upNewEventAttachmentTable(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE todo_event_attachment(
+    		id_attachment SERIAL PRIMARY KEY,
+    		id_event integer NOT NULL,
+    		id_user text NOT NULL,
+    		kind text NOT NULL CHECK (kind IN ('document', 'photo')),
+    		file_id text NOT NULL,
+    		file_unique_id text NOT NULL,
+    		file_name text NOT NULL DEFAULT '',
+    		mime_type text NOT NULL DEFAULT '',
+    		file_size bigint NOT NULL DEFAULT 0,
+    		caption text NOT NULL DEFAULT '',
+    		created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    		UNIQUE (id_event, file_unique_id),
+    		FOREIGN KEY (id_event) REFERENCES todo_event(id_event) ON DELETE CASCADE,
+    		FOREIGN KEY (id_user) REFERENCES todo_user(id_user)
+		);
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downNewEventAttachmentTable(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	_, err := tx.ExecContext(ctx, `
+		DROP TABLE todo_event_attachment;
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
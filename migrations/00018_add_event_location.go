@@ -0,0 +1,42 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddEventLocation, downAddEventLocation)
+}
+
+func upAddEventLocation(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	_, err := tx.ExecContext(ctx, `
+		ALTER TABLE todo_event
+    		ADD COLUMN location_title text NOT NULL DEFAULT '',
+    		ADD COLUMN location_address text NOT NULL DEFAULT '',
+    		ADD COLUMN latitude double precision,
+    		ADD COLUMN longitude double precision;
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downAddEventLocation(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	_, err := tx.ExecContext(ctx, `
+		ALTER TABLE todo_event
+    		DROP COLUMN longitude,
+    		DROP COLUMN latitude,
+    		DROP COLUMN location_address,
+    		DROP COLUMN location_title;
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
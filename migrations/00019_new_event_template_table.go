@@ -0,0 +1,56 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upNewEventTemplateTable, downNewEventTemplateTable)
+}
+
+func upNewEventTemplateTable(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	// Шаблон без id_user доступен всей группе
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE todo_event_template(
+    		id_template SERIAL PRIMARY KEY,
+    		name text NOT NULL,
+    		id_group integer NOT NULL,
+    		id_user text,
+    		id_creator text NOT NULL,
+    		name_event text NOT NULL,
+    		category text NOT NULL,
+    		duration bigint NOT NULL DEFAULT 0,
+    		is_all_day boolean NOT NULL DEFAULT false,
+    		link_to_video text NOT NULL DEFAULT '',
+    		location_title text NOT NULL DEFAULT '',
+    		location_address text NOT NULL DEFAULT '',
+    		latitude double precision,
+    		longitude double precision,
+    		created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    		FOREIGN KEY (id_group) REFERENCES todo_group(id_group) ON DELETE CASCADE,
+    		FOREIGN KEY (id_user) REFERENCES todo_user(id_user) ON DELETE CASCADE,
+    		FOREIGN KEY (id_creator) REFERENCES todo_user(id_user)
+		);
+
+		CREATE INDEX idx_todo_event_template_id_group ON todo_event_template(id_group);
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downNewEventTemplateTable(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	_, err := tx.ExecContext(ctx, `
+		DROP TABLE todo_event_template;
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
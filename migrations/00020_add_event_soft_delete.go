@@ -0,0 +1,43 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddEventSoftDelete, downAddEventSoftDelete)
+}
+
+func upAddEventSoftDelete(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	_, err := tx.ExecContext(ctx, `
+		ALTER TABLE todo_event
+    		ADD COLUMN deleted_at TIMESTAMPTZ;
+
+		CREATE INDEX idx_todo_event_deleted_at ON todo_event(deleted_at);
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downAddEventSoftDelete(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	// Мероприятия из корзины удаляются окончательно
+	_, err := tx.ExecContext(ctx, `
+		DELETE FROM todo_event WHERE deleted_at IS NOT NULL;
+
+		DROP INDEX idx_todo_event_deleted_at;
+
+		ALTER TABLE todo_event
+    		DROP COLUMN deleted_at;
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upNewDigestSettingTable, downNewDigestSettingTable)
+}
+
+func upNewDigestSettingTable(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE todo_digest_setting(
+    		id_user text PRIMARY KEY,
+    		enabled boolean NOT NULL DEFAULT false,
+    		send_hour integer NOT NULL DEFAULT 8 CHECK (send_hour BETWEEN 0 AND 23),
+    		send_minute integer NOT NULL DEFAULT 0 CHECK (send_minute BETWEEN 0 AND 59),
+    		skip_empty boolean NOT NULL DEFAULT true,
+    		last_sent_on date,
+    		FOREIGN KEY (id_user) REFERENCES todo_user(id_user) ON DELETE CASCADE
+		);
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downNewDigestSettingTable(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	_, err := tx.ExecContext(ctx, `
+		DROP TABLE todo_digest_setting;
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
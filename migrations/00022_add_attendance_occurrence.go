@@ -0,0 +1,51 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddAttendanceOccurrence, downAddAttendanceOccurrence)
+}
+
+func upAddAttendanceOccurrence(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	_, err := tx.ExecContext(ctx, `
+		ALTER TABLE todo_attendance
+    		ADD COLUMN occurrence_start TIMESTAMPTZ;
+		UPDATE todo_attendance a
+		SET occurrence_start = e.datetime_start
+		FROM todo_event e
+		WHERE e.id_event = a.id_event;
+		ALTER TABLE todo_attendance
+    		ALTER COLUMN occurrence_start SET NOT NULL,
+    		DROP CONSTRAINT todo_attendance_id_event_id_user_key,
+    		ADD UNIQUE (id_event, occurrence_start, id_user);
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downAddAttendanceOccurrence(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	_, err := tx.ExecContext(ctx, `
+		DELETE FROM todo_attendance a
+		USING todo_attendance newer
+		WHERE newer.id_event = a.id_event
+		  AND newer.id_user = a.id_user
+		  AND (newer.updated_at, newer.occurrence_start) > (a.updated_at, a.occurrence_start);
+		ALTER TABLE todo_attendance
+    		DROP CONSTRAINT todo_attendance_id_event_occurrence_start_id_user_key,
+    		DROP COLUMN occurrence_start,
+    		ADD UNIQUE (id_event, id_user);
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
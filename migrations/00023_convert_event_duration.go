@@ -0,0 +1,38 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upConvertEventDuration, downConvertEventDuration)
+}
+
+func upConvertEventDuration(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	_, err := tx.ExecContext(ctx, `
+		ALTER TABLE todo_event
+    		ALTER COLUMN duration TYPE bigint
+    		USING (EXTRACT(EPOCH FROM duration) * 1000000000)::bigint;
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downConvertEventDuration(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	_, err := tx.ExecContext(ctx, `
+		ALTER TABLE todo_event
+    		ALTER COLUMN duration TYPE INTERVAL
+    		USING make_interval(secs => duration / 1000000000.0);
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
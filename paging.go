@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// ---- Функционал постраничного просмотра списков ----
+
+// maxMessageLength ограничение Telegram на длину текста сообщения в UTF-16 символах
+const maxMessageLength = 4096
+
+const truncatedPageNote = "…не все записи поместились в сообщение."
+
+// messageLength возвращает длину текста так, как её считает Telegram
+func messageLength(text string) int {
+	return len(utf16.Encode([]rune(text)))
+}
+
+// appendPageBlock добавляет запись к странице, если сообщение не превысит ограничение Telegram.
+// Иначе добавляет пометку о сокращении и возвращает false: остальные записи страницы не поместятся.
+func appendPageBlock(page *strings.Builder, block string) bool {
+	if messageLength(page.String())+messageLength(block)+messageLength(truncatedPageNote) > maxMessageLength {
+		page.WriteString(truncatedPageNote)
+		return false
+	}
+	page.WriteString(block)
+	return true
+}
+
+// addPageRecord добавляет запись к странице, если сообщение не превысит ограничение Telegram,
+// и возвращает false, если запись не поместилась: с неё начнётся следующая страница.
+// Первая запись страницы добавляется всегда, при необходимости укороченной, иначе листание не продвинется.
+// Укороченная запись в разметке Markdown теряет форматирование, чтобы обрезка не разорвала сущность.
+func addPageRecord(page *strings.Builder, block string, first bool, markdown bool) bool {
+	free := maxMessageLength - messageLength(page.String())
+	if messageLength(block) <= free {
+		page.WriteString(block)
+		return true
+	}
+	if !first {
+		return false
+	}
+	format := func(text string) string { return text }
+	if markdown {
+		block = markdownToPlain(block)
+		format = func(text string) string { return tgbotapi.EscapeText(tgbotapi.ModeMarkdown, text) }
+	}
+	runes := []rune(block)
+	for len(runes) > 0 && messageLength(format(string(runes)))+1 > free {
+		runes = runes[:len(runes)-1]
+	}
+	page.WriteString(format(string(runes)) + "…")
+	return true
+}
+
+// markdownToPlain убирает из текста разметку Markdown и экранирование символов
+func markdownToPlain(text string) string {
+	var plain strings.Builder
+	escaped := false
+	for _, r := range text {
+		switch {
+		case escaped:
+			plain.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case strings.ContainsRune("*_`[", r):
+			// Символы разметки пропускаются
+		default:
+			plain.WriteRune(r)
+		}
+	}
+	return plain.String()
+}
+
+// lastPageOffset возвращает смещение последней страницы списка из total записей
+func lastPageOffset(total int64) int {
+	return int((total-1)/int64(listPageSize)) * listPageSize
+}
+
+// pagerRow возвращает кнопки листания вида <prefix><смещение>. Страница начинается с записи offset
+// и показывает shown записей: следующая начинается сразу за последней показанной,
+// поэтому записи, не поместившиеся в сообщение, не теряются. Для одной страницы кнопки не нужны.
+func pagerRow(prefix string, offset, shown int, total int64) []tgbotapi.InlineKeyboardButton {
+	if offset == 0 && int64(shown) >= total {
+		return nil
+	}
+	var row []tgbotapi.InlineKeyboardButton
+	if offset > 0 {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData("◀️", fmt.Sprintf("%s%d", prefix, max(offset-listPageSize, 0))))
+	}
+	row = append(row, noopButton(fmt.Sprintf("%d–%d из %d", offset+1, offset+shown, total)))
+	if int64(offset+shown) < total {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData("▶️", fmt.Sprintf("%s%d", prefix, offset+shown)))
+	}
+	return row
+}
+
+// handlePageCallback листает список мероприятий или групп, заменяя страницу на месте
+func handlePageCallback(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+	data := callback.Data
+
+	var edit tgbotapi.EditMessageTextConfig
+	switch {
+	case strings.HasPrefix(data, "events_page_"):
+		// Данные вида events_page_<u|a>_<смещение>: u — только предстоящие, a — все
+		parts := strings.Split(strings.TrimPrefix(data, "events_page_"), "_")
+		if len(parts) != 2 {
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Неизвестное действие"))
+			return
+		}
+		offset, err := strconv.Atoi(parts[1])
+		if err != nil || offset < 0 {
+			log.Printf("Некорректные данные кнопки листания: %s", data)
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Неизвестное действие"))
+			return
+		}
+		text, keyboard, err := eventsPage(chatID, parts[0] == "u", offset)
+		if err != nil {
+			log.Printf("Ошибка получения мероприятий пользователя %d: %v", chatID, err)
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Не удалось получить мероприятия: "+err.Error()))
+			return
+		}
+		edit = tgbotapi.NewEditMessageTextAndMarkup(chatID, callback.Message.MessageID, text, keyboard)
+		edit.ParseMode = "Markdown"
+
+	case strings.HasPrefix(data, "groups_page_"):
+		offset, err := strconv.Atoi(strings.TrimPrefix(data, "groups_page_"))
+		if err != nil || offset < 0 {
+			log.Printf("Некорректные данные кнопки листания: %s", data)
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Неизвестное действие"))
+			return
+		}
+		text, keyboard, err := groupsPage(chatID, offset)
+		if err != nil {
+			log.Printf("Ошибка получения групп пользователя %d: %v", chatID, err)
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Не удалось получить группы: "+err.Error()))
+			return
+		}
+		edit = tgbotapi.NewEditMessageTextAndMarkup(chatID, callback.Message.MessageID, text, keyboard)
+
+	default:
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Неизвестное действие"))
+		return
+	}
+
+	bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+	if _, err := bot.Send(edit); err != nil {
+		log.Printf("Ошибка обновления страницы списка: %v", err)
+	}
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"aliorToDoBot/src/dateparse"
+)
+
+// ---- Функционал выбора даты и времени инлайн-кнопками ----
+
+const pickerMinuteStep = 5 // Шаг выбора минут
+
+// datePickerStep описывает шаг ввода, в котором можно выбрать дату в календаре.
+// Выбранное значение передаётся обработчику шага так же, как введённый текст.
+type datePickerStep struct {
+	withTime bool
+	handle   func(bot *tgbotapi.BotAPI, chatID int64, text string)
+}
+
+var datePickerSteps = map[string]datePickerStep{
+	"creating_event_time":         {withTime: true, handle: handleEventCreation},
+	"creating_event_all_day_date": {withTime: false, handle: handleEventCreation},
+	"editing_event_time":          {withTime: true, handle: handleEventEditing},
+	"editing_event_all_day_date":  {withTime: false, handle: handleEventEditing},
+}
+
+// noopButton возвращает кнопку-надпись, нажатие на которую ничего не делает
+func noopButton(text string) tgbotapi.InlineKeyboardButton {
+	return tgbotapi.NewInlineKeyboardButtonData(text, "cal_noop")
+}
+
+// calendarKeyboard возвращает сетку месяца с переключением месяцев. Сегодняшний день выделен.
+func calendarKeyboard(month time.Time, today time.Time) tgbotapi.InlineKeyboardMarkup {
+	first := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, month.Location())
+	prev, next := first.AddDate(0, -1, 0), first.AddDate(0, 1, 0)
+
+	rows := [][]tgbotapi.InlineKeyboardButton{
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("◀️", "cal_nav_"+prev.Format("2006-01")),
+			noopButton(fmt.Sprintf("%s %d", monthNames[first.Month()-1], first.Year())),
+			tgbotapi.NewInlineKeyboardButtonData("▶️", "cal_nav_"+next.Format("2006-01")),
+		),
+	}
+	var weekdays []tgbotapi.InlineKeyboardButton
+	for _, name := range []string{"Пн", "Вт", "Ср", "Чт", "Пт", "Сб", "Вс"} {
+		weekdays = append(weekdays, noopButton(name))
+	}
+	rows = append(rows, weekdays)
+
+	// Неделя начинается с понедельника: пустые клетки до первого числа
+	var week []tgbotapi.InlineKeyboardButton
+	for i := 0; i < (int(first.Weekday())+6)%7; i++ {
+		week = append(week, noopButton(" "))
+	}
+	for day := first; day.Before(next); day = day.AddDate(0, 0, 1) {
+		label := strconv.Itoa(day.Day())
+		if day.Year() == today.Year() && day.YearDay() == today.YearDay() {
+			label = "•" + label
+		}
+		week = append(week, tgbotapi.NewInlineKeyboardButtonData(label, "cal_day_"+day.Format("2006-01-02")))
+		if len(week) == 7 {
+			rows = append(rows, week)
+			week = nil
+		}
+	}
+	if len(week) > 0 {
+		for len(week) < 7 {
+			week = append(week, noopButton(" "))
+		}
+		rows = append(rows, week)
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// hourKeyboard возвращает выбор часа для выбранного дня
+func hourKeyboard(day string) tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	var row []tgbotapi.InlineKeyboardButton
+	for hour := 0; hour < 24; hour++ {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("%02d", hour),
+			fmt.Sprintf("tp_h_%s_%02d", day, hour)))
+		if len(row) == 6 {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("« Календарь", "cal_nav_"+day[:7]),
+	))
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// minuteKeyboard возвращает выбор минут для выбранного дня и часа
+func minuteKeyboard(day string, hour string) tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	var row []tgbotapi.InlineKeyboardButton
+	for minute := 0; minute < 60; minute += pickerMinuteStep {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("%s:%02d", hour, minute),
+			fmt.Sprintf("tp_m_%s_%s_%02d", day, hour, minute)))
+		if len(row) == 4 {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("« Часы", "tp_d_"+day),
+	))
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// sendDatePicker отправляет календарь на текущий месяц в дополнение к текстовому вводу
+func sendDatePicker(bot *tgbotapi.BotAPI, chatID int64) {
+	now := time.Now().In(userLocation(chatID))
+	msg := tgbotapi.NewMessage(chatID, "Или выберите дату в календаре:")
+	msg.ReplyMarkup = calendarKeyboard(now, now)
+	if _, err := bot.Send(msg); err != nil {
+		log.Printf("Ошибка отправки сообщения: %v", err)
+	}
+}
+
+// editPicker заменяет текст и кнопки сообщения с календарём
+func editPicker(bot *tgbotapi.BotAPI, message *tgbotapi.Message, text string, keyboard *tgbotapi.InlineKeyboardMarkup) {
+	edit := tgbotapi.NewEditMessageText(message.Chat.ID, message.MessageID, text)
+	edit.ReplyMarkup = keyboard
+	if _, err := bot.Send(edit); err != nil {
+		log.Printf("Ошибка обновления календаря: %v", err)
+	}
+}
+
+// handlePickerCallback обрабатывает кнопки календаря и выбора времени
+func handlePickerCallback(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+	data := callback.Data
+
+	if data == "cal_noop" {
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		return
+	}
+
+	step, ok := datePickerSteps[userSteps[chatID]]
+	if !ok {
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Выбор даты уже неактуален."))
+		return
+	}
+	loc := userLocation(chatID)
+
+	switch {
+	case strings.HasPrefix(data, "cal_nav_"):
+		month, err := time.ParseInLocation("2006-01", strings.TrimPrefix(data, "cal_nav_"), loc)
+		if err != nil {
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Неизвестное действие"))
+			return
+		}
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		keyboard := calendarKeyboard(month, time.Now().In(loc))
+		editPicker(bot, callback.Message, "Выберите дату:", &keyboard)
+
+	case strings.HasPrefix(data, "cal_day_"), strings.HasPrefix(data, "tp_d_"):
+		day := strings.TrimPrefix(strings.TrimPrefix(data, "cal_day_"), "tp_d_")
+		date, err := time.ParseInLocation("2006-01-02", day, loc)
+		if err != nil {
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Неизвестное действие"))
+			return
+		}
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		if step.withTime {
+			keyboard := hourKeyboard(day)
+			editPicker(bot, callback.Message, dateparse.Describe(date, false)+"\nВыберите час:", &keyboard)
+			return
+		}
+		editPicker(bot, callback.Message, "Выбрано: "+dateparse.Describe(date, false), nil)
+		step.handle(bot, chatID, date.Format("02.01.2006"))
+
+	case strings.HasPrefix(data, "tp_h_"):
+		// Данные вида tp_h_<гггг-мм-дд>_<чч>
+		parts := strings.Split(strings.TrimPrefix(data, "tp_h_"), "_")
+		if len(parts) != 2 {
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Неизвестное действие"))
+			return
+		}
+		date, err := time.ParseInLocation("2006-01-02", parts[0], loc)
+		if err != nil {
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Неизвестное действие"))
+			return
+		}
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		keyboard := minuteKeyboard(parts[0], parts[1])
+		editPicker(bot, callback.Message, dateparse.Describe(date, false)+"\nВыберите минуты:", &keyboard)
+
+	case strings.HasPrefix(data, "tp_m_"):
+		// Данные вида tp_m_<гггг-мм-дд>_<чч>_<мм>
+		parts := strings.Split(strings.TrimPrefix(data, "tp_m_"), "_")
+		if len(parts) != 3 {
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Неизвестное действие"))
+			return
+		}
+		start, err := time.ParseInLocation("2006-01-02 15:04", parts[0]+" "+parts[1]+":"+parts[2], loc)
+		if err != nil {
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Неизвестное действие"))
+			return
+		}
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		editPicker(bot, callback.Message, "Выбрано: "+dateparse.Describe(start, true), nil)
+		step.handle(bot, chatID, start.Format("02.01.2006 15:04"))
+
+	default:
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Неизвестное действие"))
+	}
+}
@@ -0,0 +1,438 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"aliorToDoBot/src/dateparse"
+	"aliorToDoBot/src/db"
+	gorm_models2 "aliorToDoBot/src/db/gorm_models"
+	"aliorToDoBot/src/recurrence"
+)
+
+// ---- Функционал повторяющихся мероприятий ----
+
+// occurrence одно конкретное повторение мероприятия.
+// Event содержит фактическое время начала и продолжительность с учётом переноса.
+type occurrence struct {
+	Event         gorm_models2.Event
+	OriginalStart time.Time
+}
+
+var (
+	moveOccurrence      = make(map[int64]occurrence) // Повторение, которое пользователь переносит
+	moveOccurrenceStart = make(map[int64]time.Time)  // Новое время повторения, ожидающее подтверждения
+)
+
+func recurrenceKeyboard() tgbotapi.ReplyKeyboardMarkup {
+	return tgbotapi.ReplyKeyboardMarkup{
+		Keyboard: [][]tgbotapi.KeyboardButton{
+			{tgbotapi.NewKeyboardButton("Не повторять")},
+			{tgbotapi.NewKeyboardButton("Каждый день"), tgbotapi.NewKeyboardButton("По будням")},
+			{tgbotapi.NewKeyboardButton("Каждую неделю"), tgbotapi.NewKeyboardButton("Каждый месяц")},
+			{tgbotapi.NewKeyboardButton("Главное меню")},
+		},
+		ResizeKeyboard: true,
+	}
+}
+
+// parseRecurrenceInput преобразует выбор пользователя или правило RRULE в правило повторения
+func parseRecurrenceInput(text string) (recurrence.Rule, error) {
+	switch text {
+	case "Не повторять":
+		return recurrence.Rule{}, nil
+	case "Каждый день":
+		return recurrence.Rule{Freq: recurrence.Daily, Interval: 1}, nil
+	case "По будням":
+		return recurrence.Rule{Freq: recurrence.Weekly, Interval: 1, Weekdays: []time.Weekday{
+			time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday,
+		}}, nil
+	case "Каждую неделю":
+		return recurrence.Rule{Freq: recurrence.Weekly, Interval: 1}, nil
+	case "Каждый месяц":
+		return recurrence.Rule{Freq: recurrence.Monthly, Interval: 1}, nil
+	}
+	return recurrence.Parse(text)
+}
+
+// eventRule собирает правило повторения из полей мероприятия
+func eventRule(event gorm_models2.Event) recurrence.Rule {
+	weekdays, err := recurrence.ParseWeekdays(event.RecurWeekdays)
+	if err != nil {
+		log.Printf("Некорректные дни недели у мероприятия ID %d: %v", event.IDEvent, err)
+	}
+	rule := recurrence.Rule{
+		Freq:     event.RecurFreq,
+		Interval: event.RecurInterval,
+		Weekdays: weekdays,
+		Count:    event.RecurCount,
+	}
+	if event.RecurUntil != nil {
+		// Дата окончания включается целиком в часовом поясе мероприятия
+		year, month, day := event.RecurUntil.UTC().Date()
+		until := time.Date(year, month, day, 23, 59, 59, 0, eventLocation(event))
+		rule.Until = &until
+	}
+	return rule
+}
+
+// setEventRule записывает правило повторения в поля мероприятия
+func setEventRule(event *gorm_models2.Event, rule recurrence.Rule) {
+	event.RecurFreq = rule.Freq
+	event.RecurInterval = rule.Interval
+	if event.RecurInterval < 1 {
+		event.RecurInterval = 1
+	}
+	event.RecurWeekdays = recurrence.FormatWeekdays(rule.Weekdays)
+	event.RecurUntil = rule.Until
+	event.RecurCount = rule.Count
+}
+
+// firstOccurrenceStart возвращает начало первого повторения мероприятия. Оно позже DatetimeStart,
+// если день начала не подходит под правило. Второе значение равно false, если повторений нет совсем.
+func firstOccurrenceStart(event gorm_models2.Event) (time.Time, bool) {
+	start := event.DatetimeStart.In(eventLocation(event))
+	return eventRule(event).Next(start, start.Add(-time.Nanosecond))
+}
+
+// loadExceptions загружает исключения повторяющихся мероприятий, сгруппированные по ID мероприятия
+func loadExceptions(events []gorm_models2.Event) map[int64][]gorm_models2.EventException {
+	result := make(map[int64][]gorm_models2.EventException)
+
+	eventIDs := make([]int64, 0)
+	for _, event := range events {
+		if event.RecurFreq != "" {
+			eventIDs = append(eventIDs, event.IDEvent)
+		}
+	}
+
+	exceptions, err := provider.GetEventExceptions(context.Background(), eventIDs)
+	if err != nil {
+		log.Printf("Ошибка получения исключений повторений: %v", err)
+		return result
+	}
+	for _, exception := range exceptions {
+		result[exception.IDEvent] = append(result[exception.IDEvent], exception)
+	}
+	return result
+}
+
+// applyException применяет пропуск или перенос к повторению.
+// Второе значение равно false, если повторение пропущено.
+func applyException(occ occurrence, exception gorm_models2.EventException) (occurrence, bool) {
+	if exception.IsSkipped {
+		return occ, false
+	}
+	if exception.NewStart != nil {
+		occ.Event.DatetimeStart = *exception.NewStart
+	}
+	if exception.NewDuration != nil {
+		occ.Event.Duration = *exception.NewDuration
+	}
+	return occ, true
+}
+
+// eventOccurrences возвращает повторения мероприятия, пересекающиеся с интервалом [from, to).
+// Для неповторяющегося мероприятия возвращается оно само, если попадает в интервал.
+func eventOccurrences(event gorm_models2.Event, exceptions []gorm_models2.EventException, from, to time.Time) []occurrence {
+	inRange := func(occ occurrence) bool {
+		start := occ.Event.DatetimeStart
+		end := start.Add(occ.Event.Duration)
+		return start.Before(to) && (end.After(from) || !start.Before(from))
+	}
+
+	if event.RecurFreq == "" {
+		occ := occurrence{Event: event, OriginalStart: event.DatetimeStart}
+		if inRange(occ) {
+			return []occurrence{occ}
+		}
+		return nil
+	}
+
+	byStart := make(map[int64]gorm_models2.EventException)
+	for _, exception := range exceptions {
+		byStart[exception.OccurrenceStart.Unix()] = exception
+	}
+
+	// Повторения считаются по часам в часовом поясе мероприятия,
+	// чтобы переход на летнее время не сдвигал их
+	firstStart := event.DatetimeStart.In(eventLocation(event))
+
+	var result []occurrence
+	seen := make(map[int64]bool)
+	for _, start := range eventRule(event).Between(firstStart, from.Add(-event.Duration), to) {
+		occ := occurrence{Event: event, OriginalStart: start}
+		occ.Event.DatetimeStart = start
+		seen[start.Unix()] = true
+
+		if exception, ok := byStart[start.Unix()]; ok {
+			var keep bool
+			if occ, keep = applyException(occ, exception); !keep {
+				continue
+			}
+		}
+		if inRange(occ) {
+			result = append(result, occ)
+		}
+	}
+
+	// Повторения, перенесённые в интервал из-за его пределов
+	for _, exception := range exceptions {
+		if exception.NewStart == nil || seen[exception.OccurrenceStart.Unix()] {
+			continue
+		}
+		occ := occurrence{Event: event, OriginalStart: exception.OccurrenceStart}
+		occ, _ = applyException(occ, exception)
+		if inRange(occ) {
+			result = append(result, occ)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Event.DatetimeStart.Before(result[j].Event.DatetimeStart)
+	})
+	return result
+}
+
+// nextOccurrences возвращает до limit ближайших повторений, начинающихся после after
+func nextOccurrences(event gorm_models2.Event, exceptions []gorm_models2.EventException, after time.Time, limit int) []occurrence {
+	var result []occurrence
+	for _, occ := range eventOccurrences(event, exceptions, after, after.AddDate(1, 0, 0)) {
+		if !occ.Event.DatetimeStart.After(after) {
+			continue
+		}
+		result = append(result, occ)
+		if len(result) == limit {
+			break
+		}
+	}
+	return result
+}
+
+// occurrenceEnd возвращает окончание повторения. Повторение на весь день без продолжительности
+// длится до полуночи в часовом поясе мероприятия.
+func occurrenceEnd(event gorm_models2.Event) time.Time {
+	if event.IsAllDay && event.Duration == 0 {
+		return event.DatetimeStart.In(eventLocation(event)).AddDate(0, 0, 1)
+	}
+	return event.DatetimeStart.Add(event.Duration)
+}
+
+// currentOccurrence возвращает идущее или ближайшее повторение мероприятия.
+// Второе значение равно false, если повторений больше нет.
+func currentOccurrence(event gorm_models2.Event, exceptions []gorm_models2.EventException, now time.Time) (occurrence, bool) {
+	from := now
+	if event.IsAllDay && event.Duration == 0 {
+		// Идущее повторение на весь день началось в полночь, то есть до now
+		from = now.AddDate(0, 0, -2)
+	}
+	for _, occ := range eventOccurrences(event, exceptions, from, now.AddDate(2, 0, 0)) {
+		if !occurrenceEnd(occ.Event).Before(now) {
+			return occ, true
+		}
+	}
+	return occurrence{}, false
+}
+
+// recurringEventStatus вычисляет статус повторяющегося мероприятия по ближайшему повторению
+func recurringEventStatus(event gorm_models2.Event, exceptions []gorm_models2.EventException, now time.Time) string {
+	occ, ok := currentOccurrence(event, exceptions, now)
+	if !ok {
+		return gorm_models2.EventStatusFinished
+	}
+	if !occ.Event.DatetimeStart.After(now) {
+		return gorm_models2.EventStatusInProgress
+	}
+	return gorm_models2.EventStatusPlanned
+}
+
+// formatOccurrenceStart форматирует начало мероприятия в часовом поясе loc.
+// Дата мероприятия на весь день не зависит от зрителя и берётся в часовом поясе мероприятия.
+func formatOccurrenceStart(event gorm_models2.Event, loc *time.Location) string {
+	if event.IsAllDay {
+		return event.DatetimeStart.In(eventLocation(event)).Format("02.01.2006")
+	}
+	return event.DatetimeStart.In(loc).Format("02.01.2006 15:04")
+}
+
+// viewOccurrences показывает ближайшие повторения мероприятия с кнопками пропуска и переноса
+func viewOccurrences(bot *tgbotapi.BotAPI, chatID int64, eventID int64) {
+	var event gorm_models2.Event
+	if err := db.DB.First(&event, eventID).Error; err != nil {
+		log.Printf("Ошибка получения мероприятия: %v", err)
+		bot.Send(tgbotapi.NewMessage(chatID, "Мероприятие не найдено."))
+		return
+	}
+
+	exceptions := loadExceptions([]gorm_models2.Event{event})
+	loc := userLocation(chatID)
+	upcoming := nextOccurrences(event, exceptions[event.IDEvent], time.Now(), 5)
+	if len(upcoming) == 0 {
+		bot.Send(tgbotapi.NewMessage(chatID, "У мероприятия больше нет предстоящих повторений."))
+		return
+	}
+
+	var inlineKeyboard [][]tgbotapi.InlineKeyboardButton
+	for _, occ := range upcoming {
+		inlineKeyboard = append(inlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("⏭ "+formatOccurrenceStart(occ.Event, loc),
+				fmt.Sprintf("skip_occ_%d_%d", event.IDEvent, occ.OriginalStart.Unix())),
+			tgbotapi.NewInlineKeyboardButtonData("↪ Перенести",
+				fmt.Sprintf("move_occ_%d_%d", event.IDEvent, occ.OriginalStart.Unix())),
+		))
+	}
+
+	msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("Ближайшие повторения «%s» (%s).\n"+
+		"Нажмите на дату, чтобы пропустить повторение, или «Перенести», чтобы изменить его время:",
+		event.NameEvent, eventRule(event).Describe()))
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(inlineKeyboard...)
+	if _, err := bot.Send(msg); err != nil {
+		log.Printf("Ошибка отправки сообщения: %v", err)
+	}
+}
+
+// parseOccurrenceCallback разбирает данные вида <prefix><IDEvent>_<unix-время повторения>
+func parseOccurrenceCallback(data, prefix string) (int64, time.Time, error) {
+	eventPart, startPart, found := strings.Cut(strings.TrimPrefix(data, prefix), "_")
+	if !found {
+		return 0, time.Time{}, fmt.Errorf("некорректные данные: %s", data)
+	}
+	eventID, err := strconv.ParseInt(eventPart, 10, 64)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	unix, err := strconv.ParseInt(startPart, 10, 64)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	return eventID, time.Unix(unix, 0).UTC(), nil
+}
+
+// handleOccurrenceCallback обрабатывает кнопки управления повторениями
+func handleOccurrenceCallback(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+	data := callback.Data
+
+	switch {
+	case strings.HasPrefix(data, "occurrences_"):
+		eventID, err := strconv.ParseInt(strings.TrimPrefix(data, "occurrences_"), 10, 64)
+		if err != nil {
+			log.Printf("Ошибка преобразования ID мероприятия: %v", err)
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Некорректный ID мероприятия."))
+			return
+		}
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		viewOccurrences(bot, chatID, eventID)
+
+	case strings.HasPrefix(data, "skip_occ_"):
+		eventID, start, err := parseOccurrenceCallback(data, "skip_occ_")
+		if err != nil {
+			log.Printf("Ошибка обработки повторения: %v", err)
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Некорректное повторение."))
+			return
+		}
+		if err = provider.SkipOccurrence(context.Background(), chatID, eventID, start); err != nil {
+			log.Printf("Ошибка пропуска повторения мероприятия ID %d: %v", eventID, err)
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Не удалось пропустить повторение: "+err.Error()))
+			return
+		}
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Повторение пропущено."))
+		viewOccurrences(bot, chatID, eventID)
+
+	case strings.HasPrefix(data, "move_occ_"):
+		eventID, start, err := parseOccurrenceCallback(data, "move_occ_")
+		if err != nil {
+			log.Printf("Ошибка обработки повторения: %v", err)
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Некорректное повторение."))
+			return
+		}
+		var event gorm_models2.Event
+		if err = db.DB.First(&event, eventID).Error; err != nil {
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Мероприятие не найдено."))
+			return
+		}
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+
+		occ := occurrence{Event: event, OriginalStart: start}
+		occ.Event.DatetimeStart = start
+		moveOccurrence[chatID] = occ
+		userSteps[chatID] = "moving_occurrence"
+		bot.Send(tgbotapi.NewMessage(chatID, fmt.Sprintf("Введите новые дату и время для повторения %s, например «завтра в 15:00» или дд.мм.гггг чч:мм:",
+			formatOccurrenceStart(occ.Event, userLocation(chatID)))))
+	}
+}
+
+// handleOccurrenceMove принимает новое время переносимого повторения и его подтверждение
+func handleOccurrenceMove(bot *tgbotapi.BotAPI, chatID int64, text string) {
+	occ, ok := moveOccurrence[chatID]
+	if !ok || text == "Главное меню" {
+		delete(moveOccurrence, chatID)
+		delete(moveOccurrenceStart, chatID)
+		delete(userSteps, chatID)
+		sendMainMenu(bot, chatID)
+		return
+	}
+
+	if userSteps[chatID] == "confirming_occurrence_move" {
+		confirmOccurrenceMove(bot, chatID, occ, text)
+		return
+	}
+
+	loc := userLocation(chatID)
+	newStart, hasTime, err := dateparse.Parse(text, time.Now().In(loc))
+	if err != nil {
+		log.Printf("Ошибка парсинга даты '%s': %v", text, err)
+		bot.Send(tgbotapi.NewMessage(chatID, "Не удалось распознать дату и время. Попробуйте, например, «завтра в 15:00», "+
+			"«в пятницу 10:30» или дд.мм.гггг чч:мм."))
+		return
+	}
+	description := dateparse.Describe(newStart, hasTime)
+	if occ.Event.IsAllDay {
+		// Повторение мероприятия на весь день начинается в полночь
+		loc = eventLocation(occ.Event)
+		newStart = time.Date(newStart.Year(), newStart.Month(), newStart.Day(), 0, 0, 0, 0, loc)
+		description = dateparse.Describe(newStart, false) + ", весь день"
+	} else if !hasTime {
+		bot.Send(tgbotapi.NewMessage(chatID, "Укажите также время начала, например «"+text+" в 15:00»."))
+		return
+	}
+
+	moveOccurrenceStart[chatID] = newStart
+	confirmParsedTime(bot, chatID, "confirming_occurrence_move", description)
+}
+
+// confirmOccurrenceMove переносит повторение после подтверждения распознанного времени
+func confirmOccurrenceMove(bot *tgbotapi.BotAPI, chatID int64, occ occurrence, text string) {
+	newStart, ok := moveOccurrenceStart[chatID]
+	switch {
+	case !ok || text == "Нет":
+		delete(moveOccurrenceStart, chatID)
+		userSteps[chatID] = "moving_occurrence"
+		bot.Send(tgbotapi.NewMessage(chatID, "Введите новые дату и время ещё раз, например «завтра в 15:00»:"))
+		return
+	case text != "Да":
+		bot.Send(tgbotapi.NewMessage(chatID, "Пожалуйста, ответьте 'Да' или 'Нет'."))
+		return
+	}
+
+	if err := provider.MoveOccurrence(context.Background(), chatID, occ.Event.IDEvent, occ.OriginalStart, newStart); err != nil {
+		log.Printf("Ошибка переноса повторения мероприятия ID %d: %v", occ.Event.IDEvent, err)
+		bot.Send(tgbotapi.NewMessage(chatID, "Не удалось перенести повторение: "+err.Error()))
+		return
+	}
+
+	delete(moveOccurrence, chatID)
+	delete(moveOccurrenceStart, chatID)
+	delete(userSteps, chatID)
+	moved := occ.Event
+	moved.DatetimeStart = newStart
+	bot.Send(tgbotapi.NewMessage(chatID, "Повторение перенесено на "+formatOccurrenceStart(moved, userLocation(chatID))+"."))
+	viewOccurrences(bot, chatID, occ.Event.IDEvent)
+}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"aliorToDoBot/src/config"
+	"aliorToDoBot/src/db"
+	gorm_models2 "aliorToDoBot/src/db/gorm_models"
+)
+
+// ---- Функционал напоминаний ----
+
+// startReminderScheduler периодически проверяет приближающиеся мероприятия и рассылает напоминания
+func startReminderScheduler(ctx context.Context, bot *tgbotapi.BotAPI, cfg *config.ReminderConfig) {
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			sendDueReminders(ctx, bot, cfg)
+		}
+	}
+}
+
+// maxReminderOffset ограничивает интервал напоминания, чтобы не выбирать из базы слишком много мероприятий
+const maxReminderOffset = 30 * 24 * time.Hour
+
+// parseReminderOffsets разбирает список интервалов вида "1d, 1h, 10m"
+func parseReminderOffsets(text string) ([]time.Duration, error) {
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || r == ' ' || r == ';'
+	})
+	if len(fields) == 0 {
+		return nil, errors.New("не указано ни одного интервала")
+	}
+
+	offsets := make([]time.Duration, 0, len(fields))
+	seen := make(map[time.Duration]bool)
+	for _, field := range fields {
+		offset, err := parseDuration(field)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", field, err)
+		}
+		if offset <= 0 || offset > maxReminderOffset {
+			return nil, fmt.Errorf("%s: интервал должен быть больше нуля и не больше 30d", field)
+		}
+		if !seen[offset] {
+			seen[offset] = true
+			offsets = append(offsets, offset)
+		}
+	}
+
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] > offsets[j] })
+	return offsets, nil
+}
+
+// formatReminderOffsets возвращает интервалы в формате хранения "1d,1h,10m"
+func formatReminderOffsets(offsets []time.Duration) string {
+	parts := make([]string, 0, len(offsets))
+	for _, offset := range offsets {
+		parts = append(parts, strings.ReplaceAll(formatDuration(offset), " ", ""))
+	}
+	return strings.Join(parts, ",")
+}
+
+// reminderOffsets выбирает интервалы напоминаний для участника:
+// настройка мероприятия, затем категории пользователя, затем его настройка по умолчанию,
+// и наконец значение из конфигурации
+func reminderOffsets(preferences []gorm_models2.ReminderPreference, event gorm_models2.Event, idUser int64,
+	cfg *config.ReminderConfig) []time.Duration {
+	var eventPref, categoryPref, defaultPref string
+	for _, preference := range preferences {
+		switch {
+		case preference.IDEvent != nil && *preference.IDEvent == event.IDEvent:
+			eventPref = preference.Offsets
+		case preference.IDUser != nil && *preference.IDUser == idUser && preference.Category == event.Category:
+			categoryPref = preference.Offsets
+		case preference.IDUser != nil && *preference.IDUser == idUser && preference.Category == "":
+			defaultPref = preference.Offsets
+		}
+	}
+
+	for _, stored := range []string{eventPref, categoryPref, defaultPref} {
+		if stored == "" {
+			continue
+		}
+		offsets, err := parseReminderOffsets(stored)
+		if err != nil {
+			log.Printf("Некорректная настройка напоминаний '%s': %v", stored, err)
+			continue
+		}
+		return offsets
+	}
+	return []time.Duration{cfg.LeadTime}
+}
+
+// reminderTime возвращает момент, когда нужно напомнить о повторении мероприятия за offset.
+// Для мероприятий на весь день напоминание приходит утром по времени участника (loc):
+// в день мероприятия или за столько дней до него, сколько полных суток в offset.
+func reminderTime(event gorm_models2.Event, offset time.Duration, cfg *config.ReminderConfig, loc *time.Location) time.Time {
+	if event.IsAllDay {
+		year, month, day := event.DatetimeStart.In(eventLocation(event)).Date()
+		days := int(offset / (24 * time.Hour))
+		return time.Date(year, month, day-days, cfg.MorningHour, 0, 0, 0, loc)
+	}
+	return event.DatetimeStart.Add(-offset)
+}
+
+// reminderDeadline возвращает момент, после которого напоминание уже неактуально
+func reminderDeadline(event gorm_models2.Event) time.Time {
+	if event.IsAllDay {
+		return event.DatetimeStart.In(eventLocation(event)).AddDate(0, 0, 1)
+	}
+	return event.DatetimeStart
+}
+
+// sendDueReminders отправляет все напоминания, время которых наступило
+func sendDueReminders(ctx context.Context, bot *tgbotapi.BotAPI, cfg *config.ReminderConfig) {
+	now := time.Now()
+	from := now.AddDate(0, 0, -1)
+	to := now.Add(maxReminderOffset).AddDate(0, 0, 1)
+
+	events, err := provider.GetReminderCandidates(ctx, from, to)
+	if err != nil {
+		log.Printf("Ошибка получения мероприятий для напоминаний: %v", err)
+		return
+	}
+	exceptions := loadExceptions(events)
+
+	for _, event := range events {
+		occurrences := eventOccurrences(event, exceptions[event.IDEvent], from, to)
+		if len(occurrences) == 0 {
+			continue
+		}
+
+		members, err := provider.GetGroupMembers(ctx, event.IDGroup)
+		if err != nil {
+			log.Printf("Ошибка получения участников группы %d: %v", event.IDGroup, err)
+			continue
+		}
+		userIDs := make([]int64, 0, len(members))
+		for _, member := range members {
+			userIDs = append(userIDs, member.IDUser)
+		}
+		preferences, err := provider.GetReminderPreferences(ctx, []int64{event.IDEvent}, userIDs)
+		if err != nil {
+			log.Printf("Ошибка получения настроек напоминаний: %v", err)
+			continue
+		}
+
+		for _, occ := range occurrences {
+			if !now.Before(reminderDeadline(occ.Event)) {
+				continue
+			}
+			for _, member := range members {
+				loc := loadLocation(member.TimeZone)
+				var due []time.Duration
+				for _, offset := range reminderOffsets(preferences, occ.Event, member.IDUser, cfg) {
+					if !now.Before(reminderTime(occ.Event, offset, cfg, loc)) {
+						due = append(due, offset)
+					}
+				}
+				if len(due) > 0 {
+					sendOccurrenceReminder(ctx, bot, occ, member, due, now)
+				}
+			}
+		}
+	}
+}
+
+// sendOccurrenceReminder отправляет участнику одно напоминание о повторении мероприятия
+// в его часовом поясе. Все наступившие интервалы отмечаются разом, чтобы после простоя бота
+// не приходило несколько напоминаний подряд.
+func sendOccurrenceReminder(ctx context.Context, bot *tgbotapi.BotAPI, occ occurrence, member gorm_models2.User,
+	due []time.Duration, now time.Time) {
+	loc := loadLocation(member.TimeZone)
+
+	var claimed []time.Duration
+	for _, offset := range due {
+		ok, err := provider.MarkReminderSent(ctx, occ.Event.IDEvent, occ.OriginalStart, member.IDUser, offset)
+		if err != nil {
+			log.Printf("Ошибка отметки напоминания для пользователя %d: %v", member.IDUser, err)
+			continue
+		}
+		if ok {
+			claimed = append(claimed, offset)
+		}
+	}
+	if len(claimed) == 0 {
+		return
+	}
+
+	var group gorm_models2.Group
+	if err := db.DB.WithContext(ctx).First(&group, occ.Event.IDGroup).Error; err != nil {
+		log.Printf("Ошибка получения группы с ID %d: %v", occ.Event.IDGroup, err)
+	}
+
+	var text string
+	switch {
+	case occ.Event.IsAllDay && formatOccurrenceStart(occ.Event, loc) == now.In(loc).Format("02.01.2006"):
+		text = "🔔 Напоминание: сегодня мероприятие\n\n"
+	case occ.Event.IsAllDay:
+		text = fmt.Sprintf("🔔 Напоминание: %s мероприятие\n\n", formatOccurrenceStart(occ.Event, loc))
+	default:
+		left := formatDuration(occ.Event.DatetimeStart.Sub(now).Round(time.Minute))
+		if left == "" {
+			left = "1m"
+		}
+		text = fmt.Sprintf("🔔 Напоминание: мероприятие начнётся через %s, в %s\n\n",
+			left, occ.Event.DatetimeStart.In(loc).Format("15:04"))
+	}
+	text += formatEvent(occ.Event, group.GroupName, loc)
+
+	msg := tgbotapi.NewMessage(member.IDChat, text)
+	msg.ParseMode = "Markdown"
+
+	// Отмечаем ответ, если участник уже сообщил, придёт ли он
+	var current string
+	attendance, err := provider.GetEventAttendance(ctx, occ.Event.IDEvent, occ.OriginalStart)
+	if err != nil {
+		log.Printf("Ошибка получения ответов на мероприятие ID %d: %v", occ.Event.IDEvent, err)
+	}
+	for _, a := range attendance {
+		if a.IDUser == member.IDUser {
+			current = a.Status
+		}
+	}
+	msg.ReplyMarkup = reminderKeyboard(occ.Event, occ.OriginalStart, current)
+	if _, err := bot.Send(msg); err != nil {
+		log.Printf("Ошибка отправки напоминания пользователю %d: %v", member.IDUser, err)
+		if isPermanentSendError(err) {
+			return
+		}
+		for _, offset := range claimed {
+			if err = provider.UnmarkReminderSent(ctx, occ.Event.IDEvent, occ.OriginalStart, member.IDUser, offset); err != nil {
+				log.Printf("Ошибка снятия отметки напоминания: %v", err)
+			}
+		}
+		return
+	}
+
+	// Место присылается отдельным сообщением, чтобы его можно было открыть на карте
+	if err := sendEventVenue(bot, member.IDChat, occ.Event); err != nil {
+		log.Printf("Ошибка отправки места мероприятия пользователю %d: %v", member.IDUser, err)
+	}
+}
+
+// isPermanentSendError сообщает, что повторная отправка не поможет: Telegram отклонил сообщение (400)
+// или пользователь заблокировал бота (403). Отметку об отправке в этом случае не снимаем,
+// иначе отправка повторялась бы при каждой проверке.
+func isPermanentSendError(err error) bool {
+	var apiErr *tgbotapi.Error
+	return errors.As(err, &apiErr) && (apiErr.Code == 400 || apiErr.Code == 403)
+}
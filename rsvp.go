@@ -0,0 +1,322 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"aliorToDoBot/src/db"
+	gorm_models2 "aliorToDoBot/src/db/gorm_models"
+)
+
+// ---- Функционал карточки мероприятия и ответов участников ----
+
+// Источник кнопок ответа: карточка мероприятия или напоминание
+const (
+	rsvpFromCard     = "c"
+	rsvpFromReminder = "r"
+)
+
+var attendanceLabels = map[string]string{
+	gorm_models2.AttendanceGoing:    "✅ Пойду",
+	gorm_models2.AttendanceMaybe:    "🤔 Возможно",
+	gorm_models2.AttendanceNotGoing: "❌ Не пойду",
+}
+
+// rsvpRow возвращает кнопки ответа на повторение мероприятия; текущий ответ отмечается точкой
+func rsvpRow(eventID int64, occurrenceStart time.Time, source string, current string) []tgbotapi.InlineKeyboardButton {
+	var row []tgbotapi.InlineKeyboardButton
+	for _, status := range []string{gorm_models2.AttendanceGoing, gorm_models2.AttendanceMaybe, gorm_models2.AttendanceNotGoing} {
+		label := attendanceLabels[status]
+		if status == current {
+			label = "• " + label
+		}
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(label,
+			fmt.Sprintf("rsvp_%s_%d_%d_%s", source, eventID, occurrenceStart.Unix(), status)))
+	}
+	return row
+}
+
+// reminderKeyboard возвращает кнопки под напоминанием: ответ на повторение мероприятия и ссылку на встречу
+func reminderKeyboard(event gorm_models2.Event, occurrenceStart time.Time, current string) tgbotapi.InlineKeyboardMarkup {
+	rows := [][]tgbotapi.InlineKeyboardButton{rsvpRow(event.IDEvent, occurrenceStart, rsvpFromReminder, current)}
+	if button, ok := meetingLinkButton(event); ok {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(button))
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// attendanceSummary перечисляет, кто придёт на мероприятие, а кто ещё не ответил
+func attendanceSummary(members []gorm_models2.User, attendance []gorm_models2.Attendance) string {
+	statuses := make(map[int64]string)
+	for _, a := range attendance {
+		statuses[a.IDUser] = a.Status
+	}
+
+	names := make(map[string][]string)
+	pending := 0
+	for _, member := range members {
+		status, ok := statuses[member.IDUser]
+		if !ok {
+			pending++
+			continue
+		}
+		names[status] = append(names[status], tgbotapi.EscapeText(tgbotapi.ModeMarkdown, member.UserName))
+	}
+
+	var summary strings.Builder
+	summary.WriteString("Участие:")
+	for _, status := range []string{gorm_models2.AttendanceGoing, gorm_models2.AttendanceMaybe, gorm_models2.AttendanceNotGoing} {
+		summary.WriteString(fmt.Sprintf("\n%s: %d", attendanceLabels[status], len(names[status])))
+		if len(names[status]) > 0 {
+			summary.WriteString(" — " + strings.Join(names[status], ", "))
+		}
+	}
+	summary.WriteString(fmt.Sprintf("\n⏳ Не ответили: %d", pending))
+	return summary.String()
+}
+
+// eventCard формирует текст и кнопки карточки мероприятия для пользователя.
+// Карточку видят только участники группы мероприятия.
+func eventCard(chatID int64, eventID int64) (string, tgbotapi.InlineKeyboardMarkup, error) {
+	ctx := context.Background()
+
+	var event gorm_models2.Event
+	if err := db.DB.Preload("Checklist").Preload("Comments").Preload("Attachments").First(&event, eventID).Error; err != nil {
+		return "", tgbotapi.InlineKeyboardMarkup{}, fmt.Errorf("мероприятие не найдено")
+	}
+
+	members, err := provider.GetGroupMembers(ctx, event.IDGroup)
+	if err != nil {
+		return "", tgbotapi.InlineKeyboardMarkup{}, err
+	}
+	var viewer *gorm_models2.User
+	for i := range members {
+		if members[i].IDChat == chatID {
+			viewer = &members[i]
+			break
+		}
+	}
+	if viewer == nil {
+		return "", tgbotapi.InlineKeyboardMarkup{}, fmt.Errorf("мероприятие не найдено")
+	}
+
+	// Ответы повторяющегося мероприятия относятся к идущему или ближайшему повторению
+	loc := userLocation(chatID)
+	occurrenceStart := event.DatetimeStart
+	occurrenceLine := ""
+	if event.RecurFreq != "" {
+		exceptions := loadExceptions([]gorm_models2.Event{event})
+		if occ, ok := currentOccurrence(event, exceptions[event.IDEvent], time.Now()); ok {
+			occurrenceStart = occ.OriginalStart
+			occurrenceLine = "Ближайшее повторение: " + formatOccurrenceStart(occ.Event, loc) + "\n"
+		}
+	}
+
+	attendance, err := provider.GetEventAttendance(ctx, eventID, occurrenceStart)
+	if err != nil {
+		return "", tgbotapi.InlineKeyboardMarkup{}, err
+	}
+	var current string
+	for _, a := range attendance {
+		if a.IDUser == viewer.IDUser {
+			current = a.Status
+		}
+	}
+
+	var group gorm_models2.Group
+	if err = db.DB.First(&group, event.IDGroup).Error; err != nil {
+		log.Printf("Ошибка получения группы с ID %d: %v", event.IDGroup, err)
+	}
+
+	isAdmin, err := provider.IsGroupAdmin(ctx, chatID, event.IDGroup)
+	if err != nil {
+		return "", tgbotapi.InlineKeyboardMarkup{}, err
+	}
+
+	rows := [][]tgbotapi.InlineKeyboardButton{rsvpRow(eventID, occurrenceStart, rsvpFromCard, current)}
+	if isAdmin {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("⏳ Кто не ответил",
+				fmt.Sprintf("rsvp_pending_%d_%d", eventID, occurrenceStart.Unix())),
+			tgbotapi.NewInlineKeyboardButtonData("✏️ Изменить", fmt.Sprintf("edit_event_%d", eventID)),
+		))
+		rows = append(rows, eventStatusRow(event))
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📋 В шаблон", fmt.Sprintf("tpl_save_%d", eventID))))
+	}
+	checklistRow := tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("☑️ Чек-лист", fmt.Sprintf("checklist_%d", eventID)),
+	)
+	if len(event.Checklist) > 0 {
+		checklistRow[0].Text += " (" + checklistProgress(event.Checklist) + ")"
+	}
+	if event.RecurFreq != "" {
+		checklistRow = append(checklistRow,
+			tgbotapi.NewInlineKeyboardButtonData("🔁 Повторения", fmt.Sprintf("occurrences_%d", eventID)))
+	}
+	rows = append(rows, checklistRow,
+		tgbotapi.NewInlineKeyboardRow(commentsButton(event), attachmentsButton(event)),
+	)
+	var linkRow []tgbotapi.InlineKeyboardButton
+	if button, ok := venueButton(event); ok {
+		linkRow = append(linkRow, button)
+	}
+	if button, ok := meetingLinkButton(event); ok {
+		linkRow = append(linkRow, button)
+	}
+	if len(linkRow) > 0 {
+		rows = append(rows, linkRow)
+	}
+
+	text := formatEvent(event, group.GroupName, loc) + "\n\n" + occurrenceLine + attendanceSummary(members, attendance)
+	return text, tgbotapi.NewInlineKeyboardMarkup(rows...), nil
+}
+
+// viewEventCard отправляет карточку мероприятия
+func viewEventCard(bot *tgbotapi.BotAPI, chatID int64, eventID int64) {
+	text, keyboard, err := eventCard(chatID, eventID)
+	if err != nil {
+		log.Printf("Ошибка получения карточки мероприятия ID %d: %v", eventID, err)
+		bot.Send(tgbotapi.NewMessage(chatID, "Не удалось открыть мероприятие: "+err.Error()))
+		return
+	}
+
+	rememberViewedEvent(chatID, eventID)
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = keyboard
+	if _, err = bot.Send(msg); err != nil {
+		log.Printf("Ошибка отправки сообщения: %v", err)
+	}
+}
+
+// viewPendingMembers показывает администратору участников, ещё не ответивших на повторение мероприятия
+func viewPendingMembers(bot *tgbotapi.BotAPI, chatID int64, eventID int64, occurrenceStart time.Time) {
+	users, err := provider.GetPendingMembers(context.Background(), chatID, eventID, occurrenceStart)
+	if err != nil {
+		log.Printf("Ошибка получения неответивших участников мероприятия ID %d: %v", eventID, err)
+		bot.Send(tgbotapi.NewMessage(chatID, "Не удалось получить список: "+err.Error()))
+		return
+	}
+	if len(users) == 0 {
+		bot.Send(tgbotapi.NewMessage(chatID, "Все участники группы ответили."))
+		return
+	}
+
+	names := make([]string, 0, len(users))
+	for _, user := range users {
+		names = append(names, "• "+user.UserName)
+	}
+	bot.Send(tgbotapi.NewMessage(chatID, "Ещё не ответили:\n"+strings.Join(names, "\n")))
+}
+
+// handleRSVPCallback обрабатывает открытие карточки мероприятия и кнопки ответа
+func handleRSVPCallback(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+	data := callback.Data
+
+	switch {
+	case strings.HasPrefix(data, "event_card_"):
+		eventID, err := strconv.ParseInt(strings.TrimPrefix(data, "event_card_"), 10, 64)
+		if err != nil {
+			log.Printf("Ошибка преобразования ID мероприятия: %v", err)
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Некорректный ID мероприятия."))
+			return
+		}
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		viewEventCard(bot, chatID, eventID)
+
+	case strings.HasPrefix(data, "rsvp_pending_"):
+		// Данные вида rsvp_pending_<IDEvent>_<начало повторения>
+		eventID, occurrenceStart, err := parseRSVPOccurrence(strings.Split(strings.TrimPrefix(data, "rsvp_pending_"), "_"))
+		if err != nil {
+			log.Printf("Некорректные данные кнопки ответа: %s", data)
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Некорректный ID мероприятия."))
+			return
+		}
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		viewPendingMembers(bot, chatID, eventID, occurrenceStart)
+
+	default:
+		// Данные вида rsvp_<источник>_<IDEvent>_<начало повторения>_<ответ>
+		parts := strings.SplitN(strings.TrimPrefix(data, "rsvp_"), "_", 4)
+		if len(parts) != 4 {
+			log.Printf("Некорректные данные кнопки ответа: %s", data)
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Неизвестное действие"))
+			return
+		}
+		source, status := parts[0], parts[3]
+		eventID, occurrenceStart, err := parseRSVPOccurrence(parts[1:3])
+		if err != nil {
+			log.Printf("Некорректные данные кнопки ответа: %s", data)
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Некорректный ID мероприятия."))
+			return
+		}
+
+		if err = provider.SetAttendance(context.Background(), chatID, eventID, occurrenceStart, status); err != nil {
+			log.Printf("Ошибка сохранения ответа на мероприятие ID %d: %v", eventID, err)
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Не удалось сохранить ответ: "+err.Error()))
+			return
+		}
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Ответ сохранён: "+attendanceLabels[status]))
+		refreshRSVPMessage(bot, callback.Message, source, eventID, occurrenceStart, status)
+	}
+}
+
+// parseRSVPOccurrence разбирает ID мероприятия и начало повторения в секундах Unix из данных кнопки
+func parseRSVPOccurrence(parts []string) (int64, time.Time, error) {
+	if len(parts) != 2 {
+		return 0, time.Time{}, fmt.Errorf("ожидались ID мероприятия и начало повторения")
+	}
+	eventID, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	unix, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	return eventID, time.Unix(unix, 0), nil
+}
+
+// refreshRSVPMessage обновляет сообщение, в котором нажали кнопку ответа:
+// карточка перерисовывается целиком, у напоминания меняются только кнопки
+func refreshRSVPMessage(bot *tgbotapi.BotAPI, message *tgbotapi.Message, source string, eventID int64,
+	occurrenceStart time.Time, status string) {
+	chatID := message.Chat.ID
+
+	if source == rsvpFromCard {
+		refreshEventCard(bot, message, eventID)
+		return
+	}
+
+	var event gorm_models2.Event
+	if err := db.DB.First(&event, eventID).Error; err != nil {
+		log.Printf("Ошибка получения мероприятия: %v", err)
+		return
+	}
+	edit := tgbotapi.NewEditMessageReplyMarkup(chatID, message.MessageID, reminderKeyboard(event, occurrenceStart, status))
+	if _, err := bot.Send(edit); err != nil {
+		log.Printf("Ошибка обновления кнопок напоминания: %v", err)
+	}
+}
+
+// refreshEventCard перерисовывает карточку мероприятия на месте
+func refreshEventCard(bot *tgbotapi.BotAPI, message *tgbotapi.Message, eventID int64) {
+	text, keyboard, err := eventCard(message.Chat.ID, eventID)
+	if err != nil {
+		log.Printf("Ошибка получения карточки мероприятия ID %d: %v", eventID, err)
+		return
+	}
+	edit := tgbotapi.NewEditMessageTextAndMarkup(message.Chat.ID, message.MessageID, text, keyboard)
+	edit.ParseMode = "Markdown"
+	if _, err = bot.Send(edit); err != nil {
+		log.Printf("Ошибка обновления карточки мероприятия: %v", err)
+	}
+}
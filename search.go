@@ -0,0 +1,390 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"aliorToDoBot/src/dateparse"
+	"aliorToDoBot/src/db"
+	gorm_models2 "aliorToDoBot/src/db/gorm_models"
+)
+
+// ---- Функционал поиска мероприятий ----
+
+var searchFilters = make(map[int64]db.EventFilter) // Текущий фильтр поиска пользователя
+
+var eventStatuses = []string{
+	gorm_models2.EventStatusPlanned,
+	gorm_models2.EventStatusInProgress,
+	gorm_models2.EventStatusFinished,
+	gorm_models2.EventStatusCancelled,
+	gorm_models2.EventStatusPostponed,
+}
+
+// startSearch открывает меню поиска. Если указан текст, сразу ищет мероприятия по названию.
+func startSearch(bot *tgbotapi.BotAPI, chatID int64, query string) {
+	delete(userSteps, chatID)
+	query = strings.TrimSpace(query)
+	if query == "" {
+		sendSearchMenu(bot, chatID)
+		return
+	}
+
+	searchFilters[chatID] = db.EventFilter{Query: query}
+	sendSearchResults(bot, chatID)
+}
+
+// describeFilter перечисляет заданные условия поиска
+func describeFilter(chatID int64, filter db.EventFilter) string {
+	if filter.IsEmpty() {
+		return "Условия не заданы: будут найдены все ваши мероприятия."
+	}
+
+	var lines []string
+	if filter.Query != "" {
+		lines = append(lines, "Название: «"+filter.Query+"»")
+	}
+	if filter.Category != "" {
+		lines = append(lines, "Категория: "+filter.Category)
+	}
+	if filter.IDGroup != 0 {
+		var group gorm_models2.Group
+		if err := db.DB.First(&group, filter.IDGroup).Error; err != nil {
+			log.Printf("Ошибка получения группы с ID %d: %v", filter.IDGroup, err)
+		}
+		lines = append(lines, "Группа: "+group.GroupName)
+	}
+	if filter.Status != "" {
+		lines = append(lines, "Статус: "+filter.Status)
+	}
+	if filter.From != nil && filter.To != nil {
+		loc := userLocation(chatID)
+		lines = append(lines, fmt.Sprintf("Период: %s — %s",
+			filter.From.In(loc).Format("02.01.2006"), filter.To.In(loc).AddDate(0, 0, -1).Format("02.01.2006")))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// searchMenu формирует текст и кнопки меню поиска с текущими условиями
+func searchMenu(chatID int64) (string, tgbotapi.InlineKeyboardMarkup) {
+	text := "🔎 Поиск мероприятий\n\n" + describeFilter(chatID, searchFilters[chatID])
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔤 Название", "find_query"),
+			tgbotapi.NewInlineKeyboardButtonData("🏷 Категория", "find_category"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("👥 Группа", "find_group"),
+			tgbotapi.NewInlineKeyboardButtonData("📌 Статус", "find_status"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📆 Период", "find_period"),
+			tgbotapi.NewInlineKeyboardButtonData("♻️ Сбросить", "find_reset"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔎 Искать", "find_page_0"),
+		),
+	)
+	return text, keyboard
+}
+
+// sendSearchMenu отправляет меню поиска
+func sendSearchMenu(bot *tgbotapi.BotAPI, chatID int64) {
+	text, keyboard := searchMenu(chatID)
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyMarkup = keyboard
+	if _, err := bot.Send(msg); err != nil {
+		log.Printf("Ошибка отправки сообщения: %v", err)
+	}
+}
+
+// searchResults формирует страницу результатов поиска с кнопками перехода к карточкам и страницам
+func searchResults(chatID int64, offset int) (string, tgbotapi.InlineKeyboardMarkup, error) {
+	filter := searchFilters[chatID]
+	events, total, err := provider.SearchEvents(context.Background(), chatID, filter, listPageSize, offset)
+	if err != nil {
+		return "", tgbotapi.InlineKeyboardMarkup{}, err
+	}
+	// После изменения мероприятий страницы может уже не быть: показываем последнюю
+	if len(events) == 0 && offset > 0 && total > 0 {
+		return searchResults(chatID, lastPageOffset(total))
+	}
+
+	menuRow := tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData("⚙️ Условия поиска", "find_menu"))
+	if total == 0 {
+		return "Ничего не найдено.\n\n" + describeFilter(chatID, filter), tgbotapi.NewInlineKeyboardMarkup(menuRow), nil
+	}
+
+	groups, err := provider.GetUserGroups(context.Background(), chatID)
+	if err != nil {
+		return "", tgbotapi.InlineKeyboardMarkup{}, err
+	}
+	groupMap := make(map[int64]string)
+	for _, group := range groups {
+		groupMap[group.IDGroup] = group.GroupName
+	}
+
+	loc := userLocation(chatID)
+
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("Найдено мероприятий: %d\n\n", total))
+	var inlineKeyboard [][]tgbotapi.InlineKeyboardButton
+	shown := 0
+	for _, event := range events {
+		if !addPageRecord(&text, formatEvent(event, groupMap[event.IDGroup], loc)+"\n\n", shown == 0, true) {
+			break
+		}
+		shown++
+		inlineKeyboard = append(inlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📅 "+event.NameEvent, fmt.Sprintf("event_card_%d", event.IDEvent)),
+		))
+	}
+
+	if pager := pagerRow("find_page_", offset, shown, total); len(pager) > 0 {
+		inlineKeyboard = append(inlineKeyboard, pager)
+	}
+	inlineKeyboard = append(inlineKeyboard, menuRow)
+	return text.String(), tgbotapi.NewInlineKeyboardMarkup(inlineKeyboard...), nil
+}
+
+// sendSearchResults отправляет первую страницу результатов поиска
+func sendSearchResults(bot *tgbotapi.BotAPI, chatID int64) {
+	text, keyboard, err := searchResults(chatID, 0)
+	if err != nil {
+		log.Printf("Ошибка поиска мероприятий пользователя %d: %v", chatID, err)
+		bot.Send(tgbotapi.NewMessage(chatID, "Не удалось выполнить поиск: "+err.Error()))
+		return
+	}
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = keyboard
+	if _, err = bot.Send(msg); err != nil {
+		log.Printf("Ошибка отправки сообщения: %v", err)
+	}
+}
+
+// editSearchMessage заменяет сообщение поиска на меню или выбор значения условия
+func editSearchMessage(bot *tgbotapi.BotAPI, message *tgbotapi.Message, text string, keyboard tgbotapi.InlineKeyboardMarkup) {
+	edit := tgbotapi.NewEditMessageTextAndMarkup(message.Chat.ID, message.MessageID, text, keyboard)
+	if _, err := bot.Send(edit); err != nil {
+		log.Printf("Ошибка обновления сообщения поиска: %v", err)
+	}
+}
+
+// handleSearchCallback обрабатывает кнопки меню и результатов поиска
+func handleSearchCallback(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+	data := callback.Data
+	ctx := context.Background()
+	filter := searchFilters[chatID]
+
+	switch {
+	case data == "find_menu":
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		text, keyboard := searchMenu(chatID)
+		editSearchMessage(bot, callback.Message, text, keyboard)
+
+	case data == "find_reset":
+		delete(searchFilters, chatID)
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Условия сброшены."))
+		text, keyboard := searchMenu(chatID)
+		editSearchMessage(bot, callback.Message, text, keyboard)
+
+	case data == "find_query":
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		userSteps[chatID] = "search_query"
+		sendInputPrompt(bot, chatID, "Введите часть названия мероприятия или «-», чтобы не учитывать название:")
+
+	case data == "find_period":
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		userSteps[chatID] = "search_period"
+		sendInputPrompt(bot, chatID, "Введите период в формате «01.03.2026 - 31.03.2026» или одну дату, "+
+			"например «завтра». Чтобы не учитывать даты, введите «-»:")
+
+	case data == "find_category":
+		categories, err := provider.GetUserCategories(ctx, chatID)
+		if err != nil {
+			log.Printf("Ошибка получения категорий пользователя %d: %v", chatID, err)
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Не удалось получить категории."))
+			return
+		}
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		// Названия категорий могут не поместиться в данные кнопки, поэтому передаётся номер в списке
+		inlineKeyboard := [][]tgbotapi.InlineKeyboardButton{
+			tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData("Любая", "find_cat_-1")),
+		}
+		for i, category := range categories {
+			inlineKeyboard = append(inlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData(category, fmt.Sprintf("find_cat_%d", i))))
+		}
+		editSearchMessage(bot, callback.Message, "Выберите категорию:", tgbotapi.NewInlineKeyboardMarkup(inlineKeyboard...))
+
+	case strings.HasPrefix(data, "find_cat_"):
+		index, err := strconv.Atoi(strings.TrimPrefix(data, "find_cat_"))
+		if err != nil {
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Неизвестное действие"))
+			return
+		}
+		filter.Category = ""
+		if index >= 0 {
+			categories, err := provider.GetUserCategories(ctx, chatID)
+			if err != nil || index >= len(categories) {
+				log.Printf("Ошибка получения категории %d пользователя %d: %v", index, chatID, err)
+				bot.Request(tgbotapi.NewCallback(callback.ID, "Категория не найдена."))
+				return
+			}
+			filter.Category = categories[index]
+		}
+		searchFilters[chatID] = filter
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		text, keyboard := searchMenu(chatID)
+		editSearchMessage(bot, callback.Message, text, keyboard)
+
+	case data == "find_group":
+		groups, err := provider.GetUserGroups(ctx, chatID)
+		if err != nil {
+			log.Printf("Ошибка получения групп пользователя %d: %v", chatID, err)
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Не удалось получить группы."))
+			return
+		}
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		inlineKeyboard := [][]tgbotapi.InlineKeyboardButton{
+			tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData("Любая", "find_grp_0")),
+		}
+		for _, group := range groups {
+			inlineKeyboard = append(inlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData(group.GroupName, fmt.Sprintf("find_grp_%d", group.IDGroup))))
+		}
+		editSearchMessage(bot, callback.Message, "Выберите группу:", tgbotapi.NewInlineKeyboardMarkup(inlineKeyboard...))
+
+	case strings.HasPrefix(data, "find_grp_"):
+		groupID, err := strconv.ParseInt(strings.TrimPrefix(data, "find_grp_"), 10, 64)
+		if err != nil {
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Неизвестное действие"))
+			return
+		}
+		filter.IDGroup = groupID
+		searchFilters[chatID] = filter
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		text, keyboard := searchMenu(chatID)
+		editSearchMessage(bot, callback.Message, text, keyboard)
+
+	case data == "find_status":
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		inlineKeyboard := [][]tgbotapi.InlineKeyboardButton{
+			tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData("Любой", "find_st_-1")),
+		}
+		for i, status := range eventStatuses {
+			inlineKeyboard = append(inlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData(status, fmt.Sprintf("find_st_%d", i))))
+		}
+		editSearchMessage(bot, callback.Message, "Выберите статус:", tgbotapi.NewInlineKeyboardMarkup(inlineKeyboard...))
+
+	case strings.HasPrefix(data, "find_st_"):
+		index, err := strconv.Atoi(strings.TrimPrefix(data, "find_st_"))
+		if err != nil || index >= len(eventStatuses) {
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Неизвестное действие"))
+			return
+		}
+		filter.Status = ""
+		if index >= 0 {
+			filter.Status = eventStatuses[index]
+		}
+		searchFilters[chatID] = filter
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		text, keyboard := searchMenu(chatID)
+		editSearchMessage(bot, callback.Message, text, keyboard)
+
+	case strings.HasPrefix(data, "find_page_"):
+		offset, err := strconv.Atoi(strings.TrimPrefix(data, "find_page_"))
+		if err != nil || offset < 0 {
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Неизвестное действие"))
+			return
+		}
+		text, keyboard, err := searchResults(chatID, offset)
+		if err != nil {
+			log.Printf("Ошибка поиска мероприятий пользователя %d: %v", chatID, err)
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Не удалось выполнить поиск: "+err.Error()))
+			return
+		}
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		edit := tgbotapi.NewEditMessageTextAndMarkup(chatID, callback.Message.MessageID, text, keyboard)
+		edit.ParseMode = "Markdown"
+		if _, err = bot.Send(edit); err != nil {
+			log.Printf("Ошибка обновления результатов поиска: %v", err)
+		}
+
+	default:
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Неизвестное действие"))
+	}
+}
+
+// handleSearchInput сохраняет введённое название или период и возвращает меню поиска
+func handleSearchInput(bot *tgbotapi.BotAPI, chatID int64, text string) {
+	if text == "Главное меню" {
+		delete(userSteps, chatID)
+		sendMainMenu(bot, chatID)
+		return
+	}
+
+	filter := searchFilters[chatID]
+	text = strings.TrimSpace(text)
+
+	switch userSteps[chatID] {
+	case "search_query":
+		filter.Query = ""
+		if text != "-" {
+			filter.Query = text
+		}
+
+	case "search_period":
+		filter.From, filter.To = nil, nil
+		if text != "-" {
+			from, to, err := parseSearchPeriod(text, userLocation(chatID))
+			if err != nil {
+				bot.Send(tgbotapi.NewMessage(chatID, "Не удалось распознать период: "+err.Error()+
+					". Пример: 01.03.2026 - 31.03.2026"))
+				return
+			}
+			filter.From, filter.To = &from, &to
+		}
+	}
+
+	searchFilters[chatID] = filter
+	delete(userSteps, chatID)
+	sendEventsMenu(bot, chatID)
+	sendSearchMenu(bot, chatID)
+}
+
+// parseSearchPeriod разбирает период из двух дат через дефис или одну дату.
+// Возвращается интервал [from, to) от начала первого дня до конца последнего.
+func parseSearchPeriod(text string, loc *time.Location) (time.Time, time.Time, error) {
+	now := time.Now().In(loc)
+	parts := strings.Split(strings.ReplaceAll(text, "—", " - "), " - ")
+	if len(parts) > 2 {
+		return time.Time{}, time.Time{}, fmt.Errorf("укажите не больше двух дат")
+	}
+
+	var days []time.Time
+	for _, part := range parts {
+		date, _, err := dateparse.Parse(strings.TrimSpace(part), now)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		year, month, day := date.Date()
+		days = append(days, time.Date(year, month, day, 0, 0, 0, 0, loc))
+	}
+
+	from, last := days[0], days[len(days)-1]
+	if last.Before(from) {
+		return time.Time{}, time.Time{}, fmt.Errorf("конец периода раньше начала")
+	}
+	return from, last.AddDate(0, 0, 1), nil
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// ---- Функционал настроек ----
+
+var reminderPrefCategory = make(map[int64]string) // Категория, для которой пользователь задаёт интервалы напоминаний
+
+func sendSettingsMenu(bot *tgbotapi.BotAPI, chatID int64) {
+	msg := tgbotapi.NewMessage(chatID, "Настройки:")
+	msg.ReplyMarkup = tgbotapi.ReplyKeyboardMarkup{
+		Keyboard: [][]tgbotapi.KeyboardButton{
+			{tgbotapi.NewKeyboardButton("Напоминания"), tgbotapi.NewKeyboardButton("Сводка")},
+			{tgbotapi.NewKeyboardButton("Часовой пояс")},
+			{tgbotapi.NewKeyboardButton("Главное меню")},
+		},
+		ResizeKeyboard: true,
+	}
+	bot.Send(msg)
+}
+
+// viewReminderSettings показывает личные интервалы напоминаний пользователя
+func viewReminderSettings(bot *tgbotapi.BotAPI, chatID int64) {
+	preferences, err := provider.GetUserReminderPreferences(context.Background(), chatID)
+	if err != nil {
+		log.Printf("Ошибка получения настроек напоминаний пользователя %d: %v", chatID, err)
+		bot.Send(tgbotapi.NewMessage(chatID, "Ошибка при получении настроек напоминаний."))
+		return
+	}
+	categories, err := provider.GetUserCategories(context.Background(), chatID)
+	if err != nil {
+		log.Printf("Ошибка получения категорий пользователя %d: %v", chatID, err)
+		bot.Send(tgbotapi.NewMessage(chatID, "Ошибка при получении категорий."))
+		return
+	}
+
+	stored := make(map[string]string)
+	for _, preference := range preferences {
+		stored[preference.Category] = preference.Offsets
+	}
+	describe := func(category string) string {
+		if offsets, ok := stored[category]; ok {
+			return strings.ReplaceAll(offsets, ",", ", ")
+		}
+		if category == "" {
+			return "стандартные"
+		}
+		return "как по умолчанию"
+	}
+
+	var message strings.Builder
+	message.WriteString("Напоминания о мероприятиях:\n\n")
+	message.WriteString("По умолчанию: " + describe("") + "\n")
+
+	inlineKeyboard := [][]tgbotapi.InlineKeyboardButton{
+		tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData("По умолчанию", "remind_pref_")),
+	}
+	for _, category := range categories {
+		message.WriteString(category + ": " + describe(category) + "\n")
+		inlineKeyboard = append(inlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(category, "remind_pref_"+category),
+		))
+	}
+	message.WriteString("\nВыберите, что изменить:")
+
+	msg := tgbotapi.NewMessage(chatID, message.String())
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(inlineKeyboard...)
+	bot.Send(msg)
+}
+
+// handleReminderSettingsCallback запрашивает новые интервалы для выбранной категории
+func handleReminderSettingsCallback(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+	category := strings.TrimPrefix(callback.Data, "remind_pref_")
+	bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+
+	reminderPrefCategory[chatID] = category
+	userSteps[chatID] = "setting_reminder_offsets"
+
+	title := "по умолчанию"
+	if category != "" {
+		title = fmt.Sprintf("для категории «%s»", category)
+	}
+	msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("Введите интервалы напоминаний %s через запятую, например: 1d, 1h, 10m.\n"+
+		"Нажмите «Сбросить», чтобы вернуть стандартные.", title))
+	msg.ReplyMarkup = tgbotapi.ReplyKeyboardMarkup{
+		Keyboard: [][]tgbotapi.KeyboardButton{
+			{tgbotapi.NewKeyboardButton("Сбросить"), tgbotapi.NewKeyboardButton("Главное меню")},
+		},
+		ResizeKeyboard: true,
+	}
+	bot.Send(msg)
+}
+
+// handleReminderSettingsInput сохраняет введённые интервалы напоминаний
+func handleReminderSettingsInput(bot *tgbotapi.BotAPI, chatID int64, text string) {
+	category, ok := reminderPrefCategory[chatID]
+	if !ok || text == "Главное меню" {
+		delete(reminderPrefCategory, chatID)
+		delete(userSteps, chatID)
+		sendMainMenu(bot, chatID)
+		return
+	}
+
+	var offsets string
+	if text != "Сбросить" {
+		parsed, err := parseReminderOffsets(text)
+		if err != nil {
+			bot.Send(tgbotapi.NewMessage(chatID, "Неверный формат интервалов: "+err.Error()+
+				"\nИспользуйте формат 1d2h3m, например: 1d, 1h, 10m."))
+			return
+		}
+		offsets = formatReminderOffsets(parsed)
+	}
+
+	if err := provider.SetUserReminderOffsets(context.Background(), chatID, category, offsets); err != nil {
+		log.Printf("Ошибка сохранения настроек напоминаний пользователя %d: %v", chatID, err)
+		bot.Send(tgbotapi.NewMessage(chatID, "Ошибка при сохранении настроек напоминаний."))
+		return
+	}
+
+	delete(reminderPrefCategory, chatID)
+	delete(userSteps, chatID)
+	bot.Send(tgbotapi.NewMessage(chatID, "Настройки напоминаний сохранены."))
+	sendSettingsMenu(bot, chatID)
+	viewReminderSettings(bot, chatID)
+}
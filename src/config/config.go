@@ -10,9 +10,12 @@ import (
 
 // Config структура, которая хранит настройки приложения
 type Config struct {
-	Telegram TelegramConfig `yaml:"telegram"`
-	UI       UIConfig       `yaml:"ui"`
-	Database DBConfig       `yaml:"database"`
+	Telegram  TelegramConfig `yaml:"telegram"`
+	UI        UIConfig       `yaml:"ui"`
+	Database  DBConfig       `yaml:"database"`
+	Reminders ReminderConfig `yaml:"reminders"`
+	Trash     TrashConfig    `yaml:"trash"`
+	Digest    DigestConfig   `yaml:"digest"`
 }
 
 // TelegramConfig хранит параметры для Telegram API
@@ -25,6 +28,26 @@ type TelegramConfig struct {
 type UIConfig struct {
 	SessionTTL      time.Duration `yaml:"session_ttl"`
 	CleanerInterval time.Duration `yaml:"cleaner_interval"`
+	PageSize        int           `yaml:"page_size"`
+}
+
+// ReminderConfig хранит параметры рассылки напоминаний о мероприятиях
+type ReminderConfig struct {
+	Interval    time.Duration `yaml:"interval"`
+	LeadTime    time.Duration `yaml:"lead_time"`
+	MorningHour int           `yaml:"morning_hour"`
+}
+
+// TrashConfig хранит параметры корзины удалённых мероприятий
+type TrashConfig struct {
+	UndoWindow    time.Duration `yaml:"undo_window"`
+	Retention     time.Duration `yaml:"retention"`
+	PurgeInterval time.Duration `yaml:"purge_interval"`
+}
+
+// DigestConfig хранит параметры рассылки ежедневных сводок
+type DigestConfig struct {
+	Interval time.Duration `yaml:"interval"`
 }
 
 // DBConfig хранит параметры для подключения к базе данных
@@ -55,9 +78,51 @@ func LoadConfig(filePath string) (*Config, error) {
 	}
 	defer file.Close()
 
-	config := &Config{}
+	config := DefaultConfig()
 	decoder := yaml.NewDecoder(file)
-	return config, decoder.Decode(config)
+	if err := decoder.Decode(config); err != nil {
+		return config, err
+	}
+	config.validate()
+	return config, nil
+}
+
+// validate заменяет недопустимые значения параметров значениями по умолчанию
+func (c *Config) validate() {
+	defaults := DefaultConfig()
+
+	// Размер страницы делит число записей при листании
+	if c.UI.PageSize <= 0 {
+		log.Printf("Некорректное значение ui.page_size: %d", c.UI.PageSize)
+		c.UI.PageSize = defaults.UI.PageSize
+	}
+
+	// Интервалы проверок задают тикеры, которые не принимают неположительную длительность
+	if c.Reminders.Interval <= 0 {
+		log.Printf("Некорректное значение reminders.interval: %v", c.Reminders.Interval)
+		c.Reminders.Interval = defaults.Reminders.Interval
+	}
+	if c.Reminders.MorningHour < 0 || c.Reminders.MorningHour > 23 {
+		log.Printf("Некорректное значение reminders.morning_hour: %d", c.Reminders.MorningHour)
+		c.Reminders.MorningHour = defaults.Reminders.MorningHour
+	}
+	if c.Trash.PurgeInterval <= 0 {
+		log.Printf("Некорректное значение trash.purge_interval: %v", c.Trash.PurgeInterval)
+		c.Trash.PurgeInterval = defaults.Trash.PurgeInterval
+	}
+	// Без положительного срока удалённое мероприятие нельзя было бы вернуть или оно сразу стиралось бы
+	if c.Trash.UndoWindow <= 0 {
+		log.Printf("Некорректное значение trash.undo_window: %v", c.Trash.UndoWindow)
+		c.Trash.UndoWindow = defaults.Trash.UndoWindow
+	}
+	if c.Trash.Retention <= 0 {
+		log.Printf("Некорректное значение trash.retention: %v", c.Trash.Retention)
+		c.Trash.Retention = defaults.Trash.Retention
+	}
+	if c.Digest.Interval <= 0 {
+		log.Printf("Некорректное значение digest.interval: %v", c.Digest.Interval)
+		c.Digest.Interval = defaults.Digest.Interval
+	}
 }
 
 // DefaultConfig возвращает конфигурацию с параметрами по умолчанию
@@ -70,6 +135,7 @@ func DefaultConfig() *Config {
 		UI: UIConfig{
 			SessionTTL:      10 * time.Minute,
 			CleanerInterval: 20 * time.Second,
+			PageSize:        5,
 		},
 		Database: DBConfig{
 			Host:     "localhost",
@@ -79,6 +145,19 @@ func DefaultConfig() *Config {
 			DBName:   "example",
 			SSLMode:  "disable",
 		},
+		Reminders: ReminderConfig{
+			Interval:    time.Minute,
+			LeadTime:    15 * time.Minute,
+			MorningHour: 9,
+		},
+		Trash: TrashConfig{
+			UndoWindow:    5 * time.Minute,
+			Retention:     30 * 24 * time.Hour,
+			PurgeInterval: time.Hour,
+		},
+		Digest: DigestConfig{
+			Interval: time.Minute,
+		},
 	}
 }
 
@@ -100,6 +179,73 @@ func (c *Config) ParseEnv() {
 			log.Printf("Ошибка парсинга UI_SESSION_TTL: %v", err)
 		}
 	}
+	if pageSize := os.Getenv("UI_PAGE_SIZE"); pageSize != "" {
+		parsedPageSize, err := strconv.Atoi(pageSize)
+		if err == nil && parsedPageSize > 0 {
+			c.UI.PageSize = parsedPageSize
+		} else {
+			log.Printf("Некорректное значение UI_PAGE_SIZE: %s", pageSize)
+		}
+	}
+
+	if interval := os.Getenv("REMINDERS_INTERVAL"); interval != "" {
+		parsedInterval, err := time.ParseDuration(interval)
+		if err == nil {
+			c.Reminders.Interval = parsedInterval
+		} else {
+			log.Printf("Ошибка парсинга REMINDERS_INTERVAL: %v", err)
+		}
+	}
+	if leadTime := os.Getenv("REMINDERS_LEAD_TIME"); leadTime != "" {
+		parsedLeadTime, err := time.ParseDuration(leadTime)
+		if err == nil {
+			c.Reminders.LeadTime = parsedLeadTime
+		} else {
+			log.Printf("Ошибка парсинга REMINDERS_LEAD_TIME: %v", err)
+		}
+	}
+	if morningHour := os.Getenv("REMINDERS_MORNING_HOUR"); morningHour != "" {
+		parsedMorningHour, err := strconv.Atoi(morningHour)
+		if err == nil {
+			c.Reminders.MorningHour = parsedMorningHour
+		} else {
+			log.Printf("Ошибка парсинга REMINDERS_MORNING_HOUR: %v", err)
+		}
+	}
+
+	if undoWindow := os.Getenv("TRASH_UNDO_WINDOW"); undoWindow != "" {
+		parsedUndoWindow, err := time.ParseDuration(undoWindow)
+		if err == nil {
+			c.Trash.UndoWindow = parsedUndoWindow
+		} else {
+			log.Printf("Ошибка парсинга TRASH_UNDO_WINDOW: %v", err)
+		}
+	}
+	if retention := os.Getenv("TRASH_RETENTION"); retention != "" {
+		parsedRetention, err := time.ParseDuration(retention)
+		if err == nil {
+			c.Trash.Retention = parsedRetention
+		} else {
+			log.Printf("Ошибка парсинга TRASH_RETENTION: %v", err)
+		}
+	}
+	if purgeInterval := os.Getenv("TRASH_PURGE_INTERVAL"); purgeInterval != "" {
+		parsedPurgeInterval, err := time.ParseDuration(purgeInterval)
+		if err == nil {
+			c.Trash.PurgeInterval = parsedPurgeInterval
+		} else {
+			log.Printf("Ошибка парсинга TRASH_PURGE_INTERVAL: %v", err)
+		}
+	}
+
+	if digestInterval := os.Getenv("DIGEST_INTERVAL"); digestInterval != "" {
+		parsedDigestInterval, err := time.ParseDuration(digestInterval)
+		if err == nil {
+			c.Digest.Interval = parsedDigestInterval
+		} else {
+			log.Printf("Ошибка парсинга DIGEST_INTERVAL: %v", err)
+		}
+	}
 
 	if host := os.Getenv("DB_HOST"); host != "" {
 		c.Database.Host = host
@@ -119,4 +265,6 @@ func (c *Config) ParseEnv() {
 	if sslMode := os.Getenv("DB_SSLMODE"); sslMode != "" {
 		c.Database.SSLMode = sslMode
 	}
+
+	c.validate()
 }
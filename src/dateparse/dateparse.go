@@ -0,0 +1,341 @@
+package dateparse
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errUnknownFormat = errors.New("не удалось распознать дату")
+
+// Форматы, которые разбираются напрямую без анализа слов
+var absoluteLayouts = []struct {
+	layout  string
+	hasTime bool
+}{
+	{"02.01.2006 15:04", true},
+	{"2.1.2006 15:04", true},
+	{"02.01.2006", false},
+	{"2.1.2006", false},
+	{"2006-01-02 15:04", true},
+	{"2006-01-02", false},
+}
+
+var (
+	reRelative = regexp.MustCompile(`(?:^|\s)(?:через|in)\s+(\d+\s*)?` +
+		`(минуты|минуту|минут|мин|часа|часов|час|ч|дня|дней|день|недели|недель|неделю|` +
+		`minutes|minute|mins|min|hours|hour|hrs|hr|h|days|day|weeks|week)(?:\s|$)`)
+	reClock    = regexp.MustCompile(`(?:^|\s)(?:(?:в|at|@)\s+)?(\d{1,2}):(\d{2})\s*(am|pm)?(?:\s|$)`)
+	reAmPm     = regexp.MustCompile(`(?:^|\s)(?:(?:в|at|@)\s+)?(\d{1,2})\s*(am|pm)(?:\s|$)`)
+	reHourOnly = regexp.MustCompile(`(?:^|\s)(?:в|at|@)\s+(\d{1,2})(?:\s*(?:ч|часа|часов|час))?(?:\s|$)`)
+	reDayMonth = regexp.MustCompile(`^(\d{1,2})\.(\d{1,2})(?:\.(\d{4}))?$`)
+	reNumber   = regexp.MustCompile(`^\d{1,4}$`)
+
+	// «9 утра», «7 вечера», «в 2 дня» приводятся к am/pm; «через 3 дня» не трогаем
+	reMorning   = regexp.MustCompile(`(\d{1,2})(:\d{2})?\s+(утра|ночи)`)
+	reEvening   = regexp.MustCompile(`(\d{1,2})(:\d{2})?\s+вечера`)
+	reAfternoon = regexp.MustCompile(`(^|\s)в\s+(\d{1,2})(:\d{2})?\s+дня`)
+)
+
+var monthPrefixes = []struct {
+	prefix string
+	month  time.Month
+}{
+	{"янв", time.January}, {"фев", time.February}, {"мар", time.March}, {"апр", time.April},
+	{"май", time.May}, {"мая", time.May}, {"июн", time.June}, {"июл", time.July},
+	{"авг", time.August}, {"сен", time.September}, {"окт", time.October}, {"ноя", time.November},
+	{"дек", time.December},
+	{"jan", time.January}, {"feb", time.February}, {"mar", time.March}, {"apr", time.April},
+	{"may", time.May}, {"jun", time.June}, {"jul", time.July}, {"aug", time.August},
+	{"sep", time.September}, {"oct", time.October}, {"nov", time.November}, {"dec", time.December},
+}
+
+var weekdayPrefixes = []struct {
+	prefix  string
+	weekday time.Weekday
+}{
+	{"пон", time.Monday}, {"вто", time.Tuesday}, {"сре", time.Wednesday}, {"чет", time.Thursday},
+	{"пят", time.Friday}, {"суб", time.Saturday}, {"вос", time.Sunday},
+	{"mon", time.Monday}, {"tue", time.Tuesday}, {"wed", time.Wednesday}, {"thu", time.Thursday},
+	{"fri", time.Friday}, {"sat", time.Saturday}, {"sun", time.Sunday},
+}
+
+var weekdayShort = map[string]time.Weekday{
+	"пн": time.Monday, "вт": time.Tuesday, "ср": time.Wednesday, "чт": time.Thursday,
+	"пт": time.Friday, "сб": time.Saturday, "вс": time.Sunday,
+}
+
+// Служебные слова, которые не влияют на результат
+var fillerWords = map[string]bool{
+	"в": true, "во": true, "на": true, "at": true, "on": true, "the": true, "next": true,
+	"следующий": true, "следующую": true, "следующее": true, "эту": true, "этот": true, "это": true,
+	"г": true, "года": true, "год": true,
+}
+
+// Parse разбирает дату и время на русском или английском относительно момента now.
+// Поддерживаются выражения вида «завтра в 15:00», «в пятницу 10:30», «через 2 часа»,
+// «25 декабря», «tomorrow 9am», а также форматы дд.мм.гггг чч:мм и дд.мм.
+// Второе значение сообщает, было ли указано время; если нет, возвращается полночь.
+// Дата без года относится к ближайшему будущему.
+func Parse(input string, now time.Time) (time.Time, bool, error) {
+	text := normalize(input)
+	if text == "" {
+		return time.Time{}, false, errUnknownFormat
+	}
+	loc := now.Location()
+
+	for _, l := range absoluteLayouts {
+		if t, err := time.ParseInLocation(l.layout, text, loc); err == nil {
+			return t, l.hasTime, nil
+		}
+	}
+
+	var (
+		date    time.Time
+		hasDate bool
+	)
+
+	// «через 2 часа», «in 30 min»
+	if m := reRelative.FindStringSubmatchIndex(text); m != nil {
+		amount := 1
+		if m[2] >= 0 {
+			amount, _ = strconv.Atoi(strings.TrimSpace(text[m[2]:m[3]]))
+		}
+		unit := text[m[4]:m[5]]
+		text = strings.TrimSpace(text[:m[0]] + " " + text[m[1]:])
+
+		switch {
+		case strings.HasPrefix(unit, "мин") || strings.HasPrefix(unit, "min"):
+			return now.Add(time.Duration(amount) * time.Minute).Truncate(time.Minute), true, checkRest(text)
+		case strings.HasPrefix(unit, "ч") || strings.HasPrefix(unit, "h"):
+			return now.Add(time.Duration(amount) * time.Hour).Truncate(time.Minute), true, checkRest(text)
+		case strings.HasPrefix(unit, "нед") || strings.HasPrefix(unit, "week"):
+			date, hasDate = dayStart(now).AddDate(0, 0, 7*amount), true
+		default:
+			date, hasDate = dayStart(now).AddDate(0, 0, amount), true
+		}
+	}
+
+	hour, minute, hasTime, text, err := extractTime(text)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	if !hasDate {
+		date, hasDate, text, err = extractDate(text, now, hasTime, hour, minute)
+		if err != nil {
+			return time.Time{}, false, err
+		}
+	}
+	if err = checkRest(text); err != nil {
+		return time.Time{}, false, err
+	}
+
+	switch {
+	case hasDate && hasTime:
+		return time.Date(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, loc), true, nil
+	case hasDate:
+		return date, false, nil
+	case hasTime:
+		// Только время: сегодня, а если оно уже прошло — завтра
+		t := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, loc)
+		if !t.After(now) {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t, true, nil
+	}
+	return time.Time{}, false, errUnknownFormat
+}
+
+// Describe возвращает дату в виде, понятном пользователю: «пятница, 23 октября 2026, 10:30»
+func Describe(t time.Time, hasTime bool) string {
+	weekdays := []string{"воскресенье", "понедельник", "вторник", "среда", "четверг", "пятница", "суббота"}
+	months := []string{"января", "февраля", "марта", "апреля", "мая", "июня",
+		"июля", "августа", "сентября", "октября", "ноября", "декабря"}
+
+	result := fmt.Sprintf("%s, %d %s %d", weekdays[t.Weekday()], t.Day(), months[t.Month()-1], t.Year())
+	if hasTime {
+		result += ", " + t.Format("15:04")
+	}
+	return result
+}
+
+func normalize(input string) string {
+	text := strings.ToLower(strings.TrimSpace(input))
+	text = strings.ReplaceAll(text, "ё", "е")
+	text = strings.NewReplacer(",", " ", "!", " ", "?", " ").Replace(text)
+	text = strings.Join(strings.Fields(text), " ")
+	text = reMorning.ReplaceAllString(text, "$1$2 am")
+	text = reEvening.ReplaceAllString(text, "$1$2 pm")
+	text = reAfternoon.ReplaceAllString(text, "${1}в $2$3 pm")
+	return text
+}
+
+func dayStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// extractTime находит и вырезает из текста время суток
+func extractTime(text string) (int, int, bool, string, error) {
+	for _, re := range []*regexp.Regexp{reClock, reAmPm, reHourOnly} {
+		m := re.FindStringSubmatch(text)
+		if m == nil {
+			continue
+		}
+
+		hour, _ := strconv.Atoi(m[1])
+		minute := 0
+		suffix := ""
+		switch re {
+		case reClock:
+			minute, _ = strconv.Atoi(m[2])
+			suffix = m[3]
+		case reAmPm:
+			suffix = m[2]
+		}
+
+		switch suffix {
+		case "am":
+			if hour == 12 {
+				hour = 0
+			}
+		case "pm":
+			if hour < 12 {
+				hour += 12
+			}
+		}
+		if hour > 23 || minute > 59 {
+			return 0, 0, false, text, fmt.Errorf("некорректное время: %s", strings.TrimSpace(m[0]))
+		}
+
+		text = strings.TrimSpace(strings.Replace(text, strings.TrimSpace(m[0]), " ", 1))
+		return hour, minute, true, text, nil
+	}
+	return 0, 0, false, text, nil
+}
+
+// extractDate находит дату в оставшихся словах и возвращает текст без них
+func extractDate(text string, now time.Time, hasTime bool, hour, minute int) (time.Time, bool, string, error) {
+	tokens := strings.Fields(text)
+	today := dayStart(now)
+	var rest []string
+
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+
+		switch token {
+		case "сегодня", "today":
+			return today, true, joinRest(rest, tokens[i+1:]), nil
+		case "завтра", "tomorrow":
+			return today.AddDate(0, 0, 1), true, joinRest(rest, tokens[i+1:]), nil
+		case "послезавтра":
+			return today.AddDate(0, 0, 2), true, joinRest(rest, tokens[i+1:]), nil
+		}
+
+		if weekday, ok := parseWeekday(token); ok {
+			days := (int(weekday) - int(today.Weekday()) + 7) % 7
+			// Сегодняшний день недели подходит, только если указанное время ещё не прошло
+			if days == 0 && (!hasTime || !time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, now.Location()).After(now)) {
+				days = 7
+			}
+			return today.AddDate(0, 0, days), true, joinRest(rest, tokens[i+1:]), nil
+		}
+
+		if m := reDayMonth.FindStringSubmatch(token); m != nil {
+			day, _ := strconv.Atoi(m[1])
+			month, _ := strconv.Atoi(m[2])
+			year := 0
+			if m[3] != "" {
+				year, _ = strconv.Atoi(m[3])
+			}
+			date, err := buildDate(day, time.Month(month), year, today)
+			return date, err == nil, joinRest(rest, tokens[i+1:]), err
+		}
+
+		// «25 декабря [2026]»
+		if reNumber.MatchString(token) && i+1 < len(tokens) {
+			if month, ok := parseMonth(tokens[i+1]); ok {
+				day, _ := strconv.Atoi(token)
+				year, consumed := optionalYear(tokens, i+2)
+				date, err := buildDate(day, month, year, today)
+				return date, err == nil, joinRest(rest, tokens[i+2+consumed:]), err
+			}
+		}
+
+		// «december 25 [2026]»
+		if month, ok := parseMonth(token); ok && i+1 < len(tokens) && reNumber.MatchString(tokens[i+1]) {
+			day, _ := strconv.Atoi(tokens[i+1])
+			year, consumed := optionalYear(tokens, i+2)
+			date, err := buildDate(day, month, year, today)
+			return date, err == nil, joinRest(rest, tokens[i+2+consumed:]), err
+		}
+
+		rest = append(rest, token)
+	}
+	return time.Time{}, false, strings.Join(rest, " "), nil
+}
+
+func optionalYear(tokens []string, i int) (int, int) {
+	if i < len(tokens) && len(tokens[i]) == 4 && reNumber.MatchString(tokens[i]) {
+		year, _ := strconv.Atoi(tokens[i])
+		return year, 1
+	}
+	return 0, 0
+}
+
+// buildDate собирает дату; если год не указан, выбирается ближайшая будущая дата
+func buildDate(day int, month time.Month, year int, today time.Time) (time.Time, error) {
+	explicitYear := year != 0
+	if !explicitYear {
+		year = today.Year()
+	}
+	date := time.Date(year, month, day, 0, 0, 0, 0, today.Location())
+	if date.Day() != day || date.Month() != month {
+		return time.Time{}, fmt.Errorf("такой даты не существует: %d.%02d", day, month)
+	}
+	if !explicitYear && date.Before(today) {
+		date = date.AddDate(1, 0, 0)
+	}
+	return date, nil
+}
+
+func parseMonth(token string) (time.Month, bool) {
+	for _, m := range monthPrefixes {
+		if strings.HasPrefix(token, m.prefix) {
+			return m.month, true
+		}
+	}
+	return 0, false
+}
+
+func parseWeekday(token string) (time.Weekday, bool) {
+	if weekday, ok := weekdayShort[token]; ok {
+		return weekday, true
+	}
+	if len([]rune(token)) < 3 {
+		return 0, false
+	}
+	for _, w := range weekdayPrefixes {
+		if strings.HasPrefix(token, w.prefix) {
+			return w.weekday, true
+		}
+	}
+	return 0, false
+}
+
+func joinRest(before []string, after []string) string {
+	return strings.Join(append(append([]string{}, before...), after...), " ")
+}
+
+// checkRest проверяет, что в тексте не осталось нераспознанных слов
+func checkRest(text string) error {
+	for _, token := range strings.Fields(text) {
+		if !fillerWords[token] {
+			return fmt.Errorf("%w: непонятно «%s»", errUnknownFormat, token)
+		}
+	}
+	return nil
+}
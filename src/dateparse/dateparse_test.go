@@ -0,0 +1,109 @@
+package dateparse
+
+import (
+	"testing"
+	"time"
+)
+
+var msk = time.FixedZone("MSK", 3*60*60)
+
+// now среда, 21 октября 2026, 12:00 по Москве
+var now = time.Date(2026, time.October, 21, 12, 0, 0, 0, msk)
+
+func at(year int, month time.Month, day, hour, minute int) time.Time {
+	return time.Date(year, month, day, hour, minute, 0, 0, msk)
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    time.Time
+		hasTime bool
+	}{
+		// Примеры из описания задачи
+		{"завтра в 15:00", at(2026, time.October, 22, 15, 0), true},
+		{"в пятницу 10:30", at(2026, time.October, 23, 10, 30), true},
+		{"через 2 часа", at(2026, time.October, 21, 14, 0), true},
+		{"25 декабря", at(2026, time.December, 25, 0, 0), false},
+		{"tomorrow 9am", at(2026, time.October, 22, 9, 0), true},
+
+		// Даты без года относятся к ближайшему будущему
+		{"21 октября", at(2026, time.October, 21, 0, 0), false},
+		{"20 октября", at(2027, time.October, 20, 0, 0), false},
+		{"15.03", at(2027, time.March, 15, 0, 0), false},
+		{"5.11 18:00", at(2026, time.November, 5, 18, 0), true},
+		{"december 25", at(2026, time.December, 25, 0, 0), false},
+		{"1 января 2027", at(2027, time.January, 1, 0, 0), false},
+
+		// Дни недели: сегодняшний подходит, только если время ещё не прошло
+		{"в среду 15:00", at(2026, time.October, 21, 15, 0), true},
+		{"в среду 10:00", at(2026, time.October, 28, 10, 0), true},
+		{"в среду", at(2026, time.October, 28, 0, 0), false},
+		{"next monday at 10am", at(2026, time.October, 26, 10, 0), true},
+		{"в пт", at(2026, time.October, 23, 0, 0), false},
+
+		// Относительные даты
+		{"сегодня в 18:00", at(2026, time.October, 21, 18, 0), true},
+		{"послезавтра", at(2026, time.October, 23, 0, 0), false},
+		{"через 3 дня", at(2026, time.October, 24, 0, 0), false},
+		{"через неделю", at(2026, time.October, 28, 0, 0), false},
+		{"in 30 min", at(2026, time.October, 21, 12, 30), true},
+
+		// Только время: сегодня, а если уже прошло — завтра
+		{"в 7 вечера", at(2026, time.October, 21, 19, 0), true},
+		{"в 9 утра", at(2026, time.October, 22, 9, 0), true},
+		{"в 3 дня", at(2026, time.October, 21, 15, 0), true},
+		{"12pm", at(2026, time.October, 22, 12, 0), true},
+
+		// Строгие форматы
+		{"25.12.2026 18:30", at(2026, time.December, 25, 18, 30), true},
+		{"25.12.2026", at(2026, time.December, 25, 0, 0), false},
+		{"2026-12-25 09:15", at(2026, time.December, 25, 9, 15), true},
+		{"  Завтра,  в 15:00!  ", at(2026, time.October, 22, 15, 0), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, hasTime, err := Parse(tt.input, now)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.input, err)
+			}
+			if !got.Equal(tt.want) || hasTime != tt.hasTime {
+				t.Errorf("Parse(%q) = %v, %v, want %v, %v", tt.input, got, hasTime, tt.want, tt.hasTime)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"когда-нибудь",
+		"завтра в 25:00",
+		"31.02",
+		"30 февраля",
+		"завтра утром",
+	}
+	for _, input := range tests {
+		if _, _, err := Parse(input, now); err == nil {
+			t.Errorf("Parse(%q) не вернул ошибку", input)
+		}
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	tests := []struct {
+		date    time.Time
+		hasTime bool
+		want    string
+	}{
+		{at(2026, time.October, 23, 10, 30), true, "пятница, 23 октября 2026, 10:30"},
+		{at(2026, time.December, 25, 0, 0), false, "пятница, 25 декабря 2026"},
+		{at(2027, time.March, 1, 9, 5), true, "понедельник, 1 марта 2027, 09:05"},
+	}
+	for _, tt := range tests {
+		if got := Describe(tt.date, tt.hasTime); got != tt.want {
+			t.Errorf("Describe(%v, %v) = %q, want %q", tt.date, tt.hasTime, got, tt.want)
+		}
+	}
+}
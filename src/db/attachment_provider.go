@@ -0,0 +1,87 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"aliorToDoBot/src/db/gorm_models"
+)
+
+type providerAttachment interface {
+	GetEventAttachments(ChatID int64, IDEvent int64) ([]gorm_models.EventAttachment, error)
+	AddEventAttachment(ChatID int64, Attachment gorm_models.EventAttachment) error
+	DeleteEventAttachment(ChatID int64, IDAttachment int64) (gorm_models.EventAttachment, error)
+}
+
+// GetEventAttachments возвращает вложения мероприятия в порядке добавления.
+// Вложения видят только участники группы мероприятия.
+func (g *GormProvider) GetEventAttachments(ctx context.Context, chatID int64, idEvent int64) ([]gorm_models.EventAttachment, error) {
+	if _, err := g.eventMember(ctx, chatID, idEvent); err != nil {
+		return nil, err
+	}
+
+	var attachments []gorm_models.EventAttachment
+	if err := g.WithContext(ctx).Where("id_event = ?", idEvent).Order("id_attachment").Find(&attachments).Error; err != nil {
+		return nil, errInternal
+	}
+	return attachments, nil
+}
+
+// AddEventAttachment прикрепляет файл к мероприятию от имени пользователя.
+// Прикреплять файлы может любой участник группы мероприятия, один файл прикрепляется один раз.
+func (g *GormProvider) AddEventAttachment(ctx context.Context, chatID int64, attachment gorm_models.EventAttachment) error {
+	user, err := g.eventMember(ctx, chatID, attachment.IDEvent)
+	if err != nil {
+		return err
+	}
+
+	attachment.IDAttachment = 0
+	attachment.IDUser = user.IDUser
+	result := g.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&attachment)
+	if result.Error != nil {
+		return errInternal
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("этот файл уже прикреплён к мероприятию")
+	}
+	return nil
+}
+
+// DeleteEventAttachment открепляет файл от мероприятия.
+// Открепить файл может тот, кто его прикрепил, или администратор группы.
+func (g *GormProvider) DeleteEventAttachment(ctx context.Context, chatID int64, idAttachment int64) (gorm_models.EventAttachment, error) {
+	var attachment gorm_models.EventAttachment
+	if err := g.WithContext(ctx).First(&attachment, idAttachment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return attachment, errNoAttachment
+		}
+		return attachment, errInternal
+	}
+	user, err := g.eventMember(ctx, chatID, attachment.IDEvent)
+	if err != nil {
+		return attachment, err
+	}
+
+	if attachment.IDUser != user.IDUser {
+		event, err := g.eventByID(ctx, attachment.IDEvent)
+		if err != nil {
+			return attachment, err
+		}
+		isAdmin, err := g.isAdmin(ctx, chatID, event.IDGroup)
+		if err != nil {
+			return attachment, err
+		}
+		if !isAdmin {
+			return attachment, fmt.Errorf("открепить файл может только тот, кто его прикрепил, или администратор группы")
+		}
+	}
+
+	if err = g.WithContext(ctx).Delete(&attachment).Error; err != nil {
+		return attachment, errInternal
+	}
+	return attachment, nil
+}
@@ -0,0 +1,114 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"aliorToDoBot/src/db/gorm_models"
+)
+
+type providerAttendance interface {
+	SetAttendance(ChatID int64, IDEvent int64, OccurrenceStart time.Time, Status string) error
+	GetEventAttendance(IDEvent int64, OccurrenceStart time.Time) ([]gorm_models.Attendance, error)
+	GetPendingMembers(ChatID int64, IDEvent int64, OccurrenceStart time.Time) ([]gorm_models.User, error)
+}
+
+// SetAttendance сохраняет ответ пользователя на повторение мероприятия, заменяя предыдущий.
+// Отвечать могут только участники группы мероприятия.
+func (g *GormProvider) SetAttendance(ctx context.Context, chatID int64, idEvent int64, occurrenceStart time.Time, status string) error {
+	switch status {
+	case gorm_models.AttendanceGoing, gorm_models.AttendanceNotGoing, gorm_models.AttendanceMaybe:
+	default:
+		return fmt.Errorf("неизвестный ответ: %s", status)
+	}
+
+	user, err := g.eventMember(ctx, chatID, idEvent)
+	if err != nil {
+		return err
+	}
+
+	if err = g.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "id_event"}, {Name: "occurrence_start"}, {Name: "id_user"}},
+		DoUpdates: clause.AssignmentColumns([]string{"status", "updated_at"}),
+	}).Create(&gorm_models.Attendance{
+		IDEvent:         idEvent,
+		OccurrenceStart: occurrenceStart,
+		IDUser:          user.IDUser,
+		Status:          status,
+	}).Error; err != nil {
+		return errInternal
+	}
+	return nil
+}
+
+// GetEventAttendance возвращает ответы участников на повторение мероприятия.
+func (g *GormProvider) GetEventAttendance(ctx context.Context, idEvent int64, occurrenceStart time.Time) ([]gorm_models.Attendance, error) {
+	var attendance []gorm_models.Attendance
+	if err := g.WithContext(ctx).
+		Where("id_event = ? AND occurrence_start = ?", idEvent, occurrenceStart).
+		Find(&attendance).Error; err != nil {
+		return nil, errInternal
+	}
+	return attendance, nil
+}
+
+// GetPendingMembers возвращает участников группы, ещё не ответивших на повторение мероприятия.
+// Список доступен только администратору группы.
+func (g *GormProvider) GetPendingMembers(ctx context.Context, chatID int64, idEvent int64, occurrenceStart time.Time) ([]gorm_models.User, error) {
+	event, err := g.eventByID(ctx, idEvent)
+	if err != nil {
+		return nil, err
+	}
+
+	isAdmin, err := g.isAdmin(ctx, chatID, event.IDGroup)
+	if err != nil {
+		return nil, err
+	}
+	if !isAdmin {
+		return nil, fmt.Errorf("список доступен только администратору группы")
+	}
+
+	var users []gorm_models.User
+	if err = g.WithContext(ctx).
+		Where("id_user IN (?)", g.WithContext(ctx).Model(&gorm_models.Membership{}).
+			Select("id_user").
+			Where("id_group = ?", event.IDGroup)).
+		Where("id_user NOT IN (?)", g.WithContext(ctx).Model(&gorm_models.Attendance{}).
+			Select("id_user").
+			Where("id_event = ? AND occurrence_start = ?", idEvent, occurrenceStart)).
+		Order("user_name").
+		Find(&users).Error; err != nil {
+		return nil, errInternal
+	}
+	return users, nil
+}
+
+// eventByID возвращает мероприятие по его ID.
+func (g *GormProvider) eventByID(ctx context.Context, idEvent int64) (gorm_models.Event, error) {
+	var event gorm_models.Event
+	if err := g.WithContext(ctx).First(&event, idEvent).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return event, errNoEvent
+		}
+		return event, errInternal
+	}
+	return event, nil
+}
+
+// eventMember возвращает пользователя, если он состоит в группе мероприятия.
+func (g *GormProvider) eventMember(ctx context.Context, chatID int64, idEvent int64) (gorm_models.User, error) {
+	event, err := g.eventByID(ctx, idEvent)
+	if err != nil {
+		return gorm_models.User{}, err
+	}
+	user, err := g.groupMember(ctx, chatID, event.IDGroup)
+	if err != nil {
+		return user, err
+	}
+	return user, nil
+}
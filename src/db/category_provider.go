@@ -0,0 +1,245 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+
+	"aliorToDoBot/src/db/gorm_models"
+)
+
+const maxCategoryNameLength = 20
+
+type providerCategory interface {
+	GetCategory(IDCategory int64) (gorm_models.Category, error)
+	GetGroupCategories(IDGroup int64) ([]gorm_models.Category, error)
+	GetUserCategories(ChatID int64) ([]string, error)
+	CreateDefaultCategories(IDGroup int64) error
+	CreateCategory(IDGroup int64, Name string, Emoji string) error
+	UpdateCategory(IDCategory int64, Name string, Emoji string) error
+	DeleteCategory(IDCategory int64) error
+}
+
+// GetCategory возвращает категорию по её ID.
+func (g *GormProvider) GetCategory(ctx context.Context, idCategory int64) (gorm_models.Category, error) {
+	var category gorm_models.Category
+	if err := g.WithContext(ctx).First(&category, idCategory).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return category, errNoCategory
+		}
+		return category, errInternal
+	}
+	return category, nil
+}
+
+// GetGroupCategories возвращает категории мероприятий группы в порядке создания.
+func (g *GormProvider) GetGroupCategories(ctx context.Context, idGroup int64) ([]gorm_models.Category, error) {
+	var categories []gorm_models.Category
+	if err := g.WithContext(ctx).Where("id_group = ?", idGroup).Order("id_category").Find(&categories).Error; err != nil {
+		return nil, errInternal
+	}
+	return categories, nil
+}
+
+// GetUserCategories возвращает названия категорий всех групп пользователя без повторов.
+func (g *GormProvider) GetUserCategories(ctx context.Context, chatID int64) ([]string, error) {
+	user, err := g.userByChatID(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	if err = g.WithContext(ctx).Model(&gorm_models.Category{}).
+		Distinct("name").
+		Where("id_group IN (?)", g.WithContext(ctx).Model(&gorm_models.Membership{}).
+			Select("id_group").
+			Where("id_user = ?", user.IDUser)).
+		Order("name").
+		Pluck("name", &names).Error; err != nil {
+		return nil, errInternal
+	}
+	return names, nil
+}
+
+// CreateDefaultCategories добавляет новой группе стандартные категории.
+func (g *GormProvider) CreateDefaultCategories(ctx context.Context, idGroup int64) error {
+	categories := make([]gorm_models.Category, 0, len(gorm_models.DefaultCategories))
+	for _, category := range gorm_models.DefaultCategories {
+		category.IDGroup = idGroup
+		categories = append(categories, category)
+	}
+	if err := g.WithContext(ctx).Create(&categories).Error; err != nil {
+		return errInternal
+	}
+	return nil
+}
+
+// CreateCategory добавляет категорию в группу.
+// Создавать категории может только администратор группы.
+func (g *GormProvider) CreateCategory(ctx context.Context, chatID int64, idGroup int64, name, emoji string) error {
+	name = strings.TrimSpace(name)
+	if err := validateCategoryName(name); err != nil {
+		return err
+	}
+
+	isAdmin, err := g.isAdmin(ctx, chatID, idGroup)
+	if err != nil {
+		return err
+	}
+	if !isAdmin {
+		return fmt.Errorf("только администратор может изменять категории")
+	}
+
+	exists, err := g.categoryExists(ctx, idGroup, name)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("категория «%s» уже есть в группе", name)
+	}
+
+	if err = g.WithContext(ctx).Create(&gorm_models.Category{
+		IDGroup: idGroup,
+		Name:    name,
+		Emoji:   emoji,
+	}).Error; err != nil {
+		return errInternal
+	}
+	return nil
+}
+
+// UpdateCategory переименовывает категорию и меняет её эмодзи.
+// Мероприятия и шаблоны группы переносятся в категорию с новым названием.
+func (g *GormProvider) UpdateCategory(ctx context.Context, chatID int64, idCategory int64, name, emoji string) error {
+	name = strings.TrimSpace(name)
+	if err := validateCategoryName(name); err != nil {
+		return err
+	}
+
+	category, err := g.categoryForAdmin(ctx, chatID, idCategory)
+	if err != nil {
+		return err
+	}
+
+	// Смена только регистра не создаёт дубликата: сама категория не считается
+	if !strings.EqualFold(name, category.Name) {
+		exists, err := g.categoryExists(ctx, category.IDGroup, name)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return fmt.Errorf("категория «%s» уже есть в группе", name)
+		}
+	}
+
+	return g.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&category).Updates(map[string]interface{}{"name": name, "emoji": emoji}).Error; err != nil {
+			return errInternal
+		}
+		if err := tx.Model(&gorm_models.Event{}).Unscoped().
+			Where("id_group = ? AND category = ?", category.IDGroup, category.Name).
+			Update("category", name).Error; err != nil {
+			return errInternal
+		}
+		if err := tx.Model(&gorm_models.EventTemplate{}).
+			Where("id_group = ? AND category = ?", category.IDGroup, category.Name).
+			Update("category", name).Error; err != nil {
+			return errInternal
+		}
+		// Настройки напоминаний по категории хранятся у пользователя, а не у группы: переносим их,
+		// только если ни в одной другой группе пользователя нет категории со старым названием
+		if err := tx.Model(&gorm_models.ReminderPreference{}).
+			Where("id_event IS NULL AND category = ?", category.Name).
+			Where("id_user IN (?)", tx.Model(&gorm_models.Membership{}).
+				Select("id_user").
+				Where("id_group = ?", category.IDGroup)).
+			Where("id_user NOT IN (?)", tx.Model(&gorm_models.Membership{}).
+				Select("id_user").
+				Where("id_group IN (?)", tx.Model(&gorm_models.Category{}).
+					Select("id_group").
+					Where("name = ? AND id_group <> ?", category.Name, category.IDGroup))).
+			Update("category", name).Error; err != nil {
+			return errInternal
+		}
+		return nil
+	})
+}
+
+// DeleteCategory удаляет категорию группы.
+// Категорию, в которой есть мероприятия, в том числе в корзине, и последнюю категорию группы удалить нельзя.
+func (g *GormProvider) DeleteCategory(ctx context.Context, chatID int64, idCategory int64) error {
+	category, err := g.categoryForAdmin(ctx, chatID, idCategory)
+	if err != nil {
+		return err
+	}
+
+	var count int64
+	if err = g.WithContext(ctx).Model(&gorm_models.Category{}).
+		Where("id_group = ?", category.IDGroup).
+		Count(&count).Error; err != nil {
+		return errInternal
+	}
+	if count <= 1 {
+		return fmt.Errorf("в группе должна остаться хотя бы одна категория")
+	}
+
+	// Мероприятия в корзине тоже считаются: после восстановления они остались бы без категории
+	if err = g.WithContext(ctx).Model(&gorm_models.Event{}).Unscoped().
+		Where("id_group = ? AND category = ?", category.IDGroup, category.Name).
+		Count(&count).Error; err != nil {
+		return errInternal
+	}
+	if count > 0 {
+		return fmt.Errorf("в категории «%s» есть мероприятия (%d), сначала перенесите их", category.Name, count)
+	}
+
+	if err = g.WithContext(ctx).Delete(&category).Error; err != nil {
+		return errInternal
+	}
+	return nil
+}
+
+// validateCategoryName проверяет название категории.
+// Длина ограничена, чтобы название помещалось в данные инлайн-кнопок Telegram.
+func validateCategoryName(name string) error {
+	if name == "" {
+		return fmt.Errorf("название категории не может быть пустым")
+	}
+	if utf8.RuneCountInString(name) > maxCategoryNameLength {
+		return fmt.Errorf("название категории должно быть не длиннее %d символов", maxCategoryNameLength)
+	}
+	return nil
+}
+
+// categoryForAdmin возвращает категорию, если пользователь администрирует её группу.
+func (g *GormProvider) categoryForAdmin(ctx context.Context, chatID int64, idCategory int64) (gorm_models.Category, error) {
+	category, err := g.GetCategory(ctx, idCategory)
+	if err != nil {
+		return category, err
+	}
+
+	isAdmin, err := g.isAdmin(ctx, chatID, category.IDGroup)
+	if err != nil {
+		return category, err
+	}
+	if !isAdmin {
+		return category, fmt.Errorf("только администратор может изменять категории")
+	}
+	return category, nil
+}
+
+// categoryExists проверяет, есть ли в группе категория с указанным названием без учёта регистра:
+// мастер создания мероприятия сопоставляет введённую категорию так же.
+func (g *GormProvider) categoryExists(ctx context.Context, idGroup int64, name string) (bool, error) {
+	var count int64
+	if err := g.WithContext(ctx).Model(&gorm_models.Category{}).
+		Where("id_group = ? AND LOWER(name) = LOWER(?)", idGroup, name).
+		Count(&count).Error; err != nil {
+		return false, errInternal
+	}
+	return count > 0, nil
+}
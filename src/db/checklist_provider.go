@@ -0,0 +1,114 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+
+	"aliorToDoBot/src/db/gorm_models"
+)
+
+const maxChecklistItemLength = 100
+
+type providerChecklist interface {
+	GetChecklist(ChatID int64, IDEvent int64) ([]gorm_models.ChecklistItem, error)
+	AddChecklistItems(ChatID int64, IDEvent int64, Texts []string) error
+	ToggleChecklistItem(ChatID int64, IDItem int64) (gorm_models.ChecklistItem, error)
+	DeleteChecklistItem(ChatID int64, IDItem int64) (gorm_models.ChecklistItem, error)
+}
+
+// GetChecklist возвращает пункты чек-листа мероприятия в порядке добавления.
+// Чек-лист видят только участники группы мероприятия.
+func (g *GormProvider) GetChecklist(ctx context.Context, chatID int64, idEvent int64) ([]gorm_models.ChecklistItem, error) {
+	if _, err := g.eventMember(ctx, chatID, idEvent); err != nil {
+		return nil, err
+	}
+
+	var items []gorm_models.ChecklistItem
+	if err := g.WithContext(ctx).Where("id_event = ?", idEvent).Order("id_item").Find(&items).Error; err != nil {
+		return nil, errInternal
+	}
+	return items, nil
+}
+
+// AddChecklistItems добавляет пункты в чек-лист мероприятия.
+// Добавлять пункты может любой участник группы мероприятия.
+func (g *GormProvider) AddChecklistItems(ctx context.Context, chatID int64, idEvent int64, texts []string) error {
+	if _, err := g.eventMember(ctx, chatID, idEvent); err != nil {
+		return err
+	}
+
+	items := make([]gorm_models.ChecklistItem, 0, len(texts))
+	for _, text := range texts {
+		text = strings.TrimSpace(text)
+		if text == "" {
+			continue
+		}
+		if utf8.RuneCountInString(text) > maxChecklistItemLength {
+			return fmt.Errorf("пункт должен быть не длиннее %d символов", maxChecklistItemLength)
+		}
+		items = append(items, gorm_models.ChecklistItem{IDEvent: idEvent, Text: text})
+	}
+	if len(items) == 0 {
+		return fmt.Errorf("пункт не может быть пустым")
+	}
+
+	if err := g.WithContext(ctx).Create(&items).Error; err != nil {
+		return errInternal
+	}
+	return nil
+}
+
+// ToggleChecklistItem отмечает пункт выполненным от имени пользователя или снимает отметку.
+func (g *GormProvider) ToggleChecklistItem(ctx context.Context, chatID int64, idItem int64) (gorm_models.ChecklistItem, error) {
+	item, err := g.checklistItem(ctx, idItem)
+	if err != nil {
+		return item, err
+	}
+	user, err := g.eventMember(ctx, chatID, item.IDEvent)
+	if err != nil {
+		return item, err
+	}
+
+	updates := map[string]interface{}{"is_done": false, "id_checked_by": nil, "checked_at": nil}
+	if !item.IsDone {
+		updates = map[string]interface{}{"is_done": true, "id_checked_by": user.IDUser, "checked_at": time.Now()}
+	}
+	if err = g.WithContext(ctx).Model(&item).Updates(updates).Error; err != nil {
+		return item, errInternal
+	}
+	return item, nil
+}
+
+// DeleteChecklistItem удаляет пункт из чек-листа мероприятия.
+func (g *GormProvider) DeleteChecklistItem(ctx context.Context, chatID int64, idItem int64) (gorm_models.ChecklistItem, error) {
+	item, err := g.checklistItem(ctx, idItem)
+	if err != nil {
+		return item, err
+	}
+	if _, err = g.eventMember(ctx, chatID, item.IDEvent); err != nil {
+		return item, err
+	}
+
+	if err = g.WithContext(ctx).Delete(&item).Error; err != nil {
+		return item, errInternal
+	}
+	return item, nil
+}
+
+// checklistItem возвращает пункт чек-листа по его ID.
+func (g *GormProvider) checklistItem(ctx context.Context, idItem int64) (gorm_models.ChecklistItem, error) {
+	var item gorm_models.ChecklistItem
+	if err := g.WithContext(ctx).First(&item, idItem).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return item, errNoChecklistItem
+		}
+		return item, errInternal
+	}
+	return item, nil
+}
@@ -0,0 +1,61 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"aliorToDoBot/src/db/gorm_models"
+)
+
+const maxCommentLength = 1000
+
+type providerComment interface {
+	GetEventComments(ChatID int64, IDEvent int64, Limit int) ([]gorm_models.EventComment, error)
+	AddEventComment(ChatID int64, IDEvent int64, Text string) (gorm_models.EventComment, error)
+}
+
+// GetEventComments возвращает последние limit комментариев к мероприятию в порядке написания.
+// Комментарии видят только участники группы мероприятия.
+func (g *GormProvider) GetEventComments(ctx context.Context, chatID int64, idEvent int64, limit int) ([]gorm_models.EventComment, error) {
+	if _, err := g.eventMember(ctx, chatID, idEvent); err != nil {
+		return nil, err
+	}
+
+	var comments []gorm_models.EventComment
+	if err := g.WithContext(ctx).Preload("User").
+		Where("id_event = ?", idEvent).
+		Order("id_comment DESC").
+		Limit(limit).
+		Find(&comments).Error; err != nil {
+		return nil, errInternal
+	}
+	for i, j := 0, len(comments)-1; i < j; i, j = i+1, j-1 {
+		comments[i], comments[j] = comments[j], comments[i]
+	}
+	return comments, nil
+}
+
+// AddEventComment добавляет комментарий пользователя к мероприятию.
+// Комментировать может любой участник группы мероприятия.
+func (g *GormProvider) AddEventComment(ctx context.Context, chatID int64, idEvent int64, text string) (gorm_models.EventComment, error) {
+	user, err := g.eventMember(ctx, chatID, idEvent)
+	if err != nil {
+		return gorm_models.EventComment{}, err
+	}
+
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return gorm_models.EventComment{}, fmt.Errorf("комментарий не может быть пустым")
+	}
+	if utf8.RuneCountInString(text) > maxCommentLength {
+		return gorm_models.EventComment{}, fmt.Errorf("комментарий должен быть не длиннее %d символов", maxCommentLength)
+	}
+
+	comment := gorm_models.EventComment{IDEvent: idEvent, IDUser: user.IDUser, User: user, Text: text}
+	if err = g.WithContext(ctx).Omit("User").Create(&comment).Error; err != nil {
+		return gorm_models.EventComment{}, errInternal
+	}
+	return comment, nil
+}
@@ -0,0 +1,31 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"aliorToDoBot/src/db/gorm_models"
+)
+
+type providerConflict interface {
+	GetUserEventsInRange(IDUser int64, From time.Time, To time.Time) ([]gorm_models.Event, error)
+}
+
+// GetUserEventsInRange возвращает мероприятия всех групп пользователя, которые могут пересекаться
+// с интервалом [from, to): начинающиеся до его конца и заканчивающиеся не раньше чем за сутки до начала,
+// а также все повторяющиеся. Отменённые мероприятия не учитываются.
+// Точное пересечение проверяется вызывающей стороной.
+func (g *GormProvider) GetUserEventsInRange(ctx context.Context, idUser int64, from, to time.Time) ([]gorm_models.Event, error) {
+	var events []gorm_models.Event
+	if err := g.WithContext(ctx).
+		Where("id_group IN (?)", g.WithContext(ctx).Model(&gorm_models.Membership{}).
+			Select("id_group").
+			Where("id_user = ?", idUser)).
+		Where("recur_freq <> '' OR (datetime_start < ? AND "+
+			"datetime_start + make_interval(secs => duration / 1000000000.0) + interval '1 day' > ?)", to, from).
+		Where("status <> ?", gorm_models.EventStatusCancelled).
+		Find(&events).Error; err != nil {
+		return nil, errInternal
+	}
+	return events, nil
+}
@@ -12,16 +12,38 @@ import (
 )
 
 var (
-	errNoGroup    = fmt.Errorf("группа не найдена")
-	errNoCategory = fmt.Errorf("категория не найдена")
-	errNoUser     = fmt.Errorf("пользователь не найден")
-	errInternal   = fmt.Errorf("системная ошибка")
+	errNoGroup         = fmt.Errorf("группа не найдена")
+	errNoCategory      = fmt.Errorf("категория не найдена")
+	errNoUser          = fmt.Errorf("пользователь не найден")
+	errNoEvent         = fmt.Errorf("событие не найдено")
+	errNoChecklistItem = fmt.Errorf("пункт чек-листа не найден")
+	errNoTask          = fmt.Errorf("задача не найдена")
+	errNoTaskColumn    = fmt.Errorf("колонка доски не найдена")
+	errNoAttachment    = fmt.Errorf("вложение не найдено")
+	errNoTemplate      = fmt.Errorf("шаблон не найден")
+	errInternal        = fmt.Errorf("системная ошибка")
 )
 
 type DatabaseProvider interface {
 	providerUser
 	providerGroup
 	providerEvent
+	providerRecurrence
+	providerReminder
+	providerCategory
+	providerConflict
+	providerAttendance
+	providerChecklist
+	providerTask
+	providerTaskColumn
+	providerEventStatus
+	providerSearch
+	providerComment
+	providerAttachment
+	providerTemplate
+	providerTrash
+	providerList
+	providerDigest
 }
 
 type providerGroup interface {
@@ -33,13 +55,18 @@ type providerGroup interface {
 type providerUser interface {
 	GetUser(ChatID int64) (int64, string, error)
 	CreateUser(ChatID int64, UserName string) error
+	GetUserTimeZone(ChatID int64) (string, error)
+	SetUserTimeZone(ChatID int64, TimeZone string) error
 }
 
 type providerEvent interface {
 	GetEvents(IDUser int64) (string, error)
 	CreateEvent(GroupName string, NameEvent string, Category string,
-		IsAllDay bool, DatetimeStart time.Time,
-		Duration time.Duration) error
+		IsAllDay bool, DatetimeStart time.Time, TimeZone string,
+		Duration time.Duration, LinkToVideo string) error
+	UpdateEvent(IDEvent int64, IDGroup int64, NameEvent string, Category string,
+		IsAllDay bool, DatetimeStart time.Time, TimeZone string,
+		Duration time.Duration, LinkToVideo string, Location gorm_models.EventLocation) error
 	DeleteEvent(NameEvent string) error
 }
 
@@ -72,12 +99,18 @@ func (g *GormProvider) CreateGroup(ctx context.Context, chatID int64, groupName
 		GroupName: groupName,
 	}
 	tx.WithContext(ctx).Create(newGroup)
+	if err := g.CreateDefaultCategories(ctx, newGroup.IDGroup); err != nil {
+		return err
+	}
+	if err := g.CreateDefaultTaskColumns(ctx, newGroup.IDGroup); err != nil {
+		return err
+	}
 	for _, v := range users {
 		isAdmin := v.IDChat == chatID
 		tx.WithContext(ctx).Create(&gorm_models.Membership{
 			IDGroup: newGroup.IDGroup,
 			IDUser:  v.IDUser,
-			IDAdmin: isAdmin,
+			IsAdmin: isAdmin,
 		})
 	}
 	return tx.Error
@@ -139,6 +172,32 @@ func (g *GormProvider) CreateUser(ctx context.Context, chatID int64, userName st
 	return nil
 }
 
+// GetUserTimeZone возвращает часовой пояс пользователя в формате IANA, например Europe/Moscow.
+func (g *GormProvider) GetUserTimeZone(ctx context.Context, chatID int64) (string, error) {
+	user, err := g.userByChatID(ctx, chatID)
+	if err != nil {
+		return "", err
+	}
+	return user.TimeZone, nil
+}
+
+// SetUserTimeZone сохраняет часовой пояс пользователя.
+// Если такого часового пояса не существует, возвращается ошибка.
+func (g *GormProvider) SetUserTimeZone(ctx context.Context, chatID int64, timeZone string) error {
+	if _, err := time.LoadLocation(timeZone); err != nil || timeZone == "" || timeZone == "Local" {
+		return fmt.Errorf("неизвестный часовой пояс: %s", timeZone)
+	}
+
+	result := g.WithContext(ctx).Model(&gorm_models.User{}).Where("id_chat = ?", chatID).Update("time_zone", timeZone)
+	if result.Error != nil {
+		return errInternal
+	}
+	if result.RowsAffected == 0 {
+		return errNoUser
+	}
+	return nil
+}
+
 // GetEvents возвращает список событий для пользователя с указанным chatID.
 // Возвращается строковое представление событий, если они найдены.
 func (g *GormProvider) GetEvents(ctx context.Context, chatID int64) (string, error) {
@@ -162,10 +221,14 @@ func (g *GormProvider) GetEvents(ctx context.Context, chatID int64) (string, err
 	if len(events) == 0 {
 		return "Нет запланированных событий", nil
 	}
+	loc, err := time.LoadLocation(user.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
 	var result string
 	for _, event := range events {
 		result += fmt.Sprintf("Событие: %s, Категория: %s, Начало: %s\n",
-			event.NameEvent, event.Category, event.DatetimeStart.Format("02.01.2006 15:04"))
+			event.NameEvent, event.Category, event.DatetimeStart.In(loc).Format("02.01.2006 15:04"))
 	}
 
 	return result, nil
@@ -174,22 +237,7 @@ func (g *GormProvider) GetEvents(ctx context.Context, chatID int64) (string, err
 // CreateEvent создает новое событие для указанной группы.
 // Проверяется наличие категории и принадлежность пользователя к группе.
 func (g *GormProvider) CreateEvent(ctx context.Context, chatID int64, groupName, nameEvent, category string,
-	isAllDay bool, datetimeStart time.Time, duration time.Duration) error {
-	var (
-		validCategories = []string{"Личное", "Семья", "Работа"}
-		isValid         bool
-	)
-
-	for _, validCategory := range validCategories {
-		if category == validCategory {
-			isValid = true
-			break
-		}
-	}
-	if !isValid {
-		return errNoCategory
-	}
-
+	isAllDay bool, datetimeStart time.Time, timeZone string, duration time.Duration, linkToVideo string) error {
 	var group gorm_models.Group
 	if err := g.WithContext(ctx).Where("group_name = ?", groupName).First(&group).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -198,6 +246,14 @@ func (g *GormProvider) CreateEvent(ctx context.Context, chatID int64, groupName,
 		return errInternal
 	}
 
+	exists, err := g.categoryExists(ctx, group.IDGroup, category)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return errNoCategory
+	}
+
 	isAdmin, err := g.isAdmin(ctx, chatID, group.IDGroup)
 	if err != nil {
 		return err
@@ -214,13 +270,62 @@ func (g *GormProvider) CreateEvent(ctx context.Context, chatID int64, groupName,
 		Category:      category,
 		Duration:      duration,
 		IsAllDay:      isAllDay,
-		Status:        "Запланировано",
+		TimeZone:      timeZone,
+		LinkToVideo:   linkToVideo,
+		Status:        gorm_models.EventStatusPlanned,
 	}
 
 	return g.WithContext(ctx).Create(newEvent).Error
 }
 
-// DeleteEvent удаляет событие с указанным именем.
+// UpdateEvent изменяет существующее событие.
+// Пользователь должен быть администратором как текущей группы события, так и новой.
+func (g *GormProvider) UpdateEvent(ctx context.Context, chatID int64, idEvent int64, idGroup int64,
+	nameEvent, category string, isAllDay bool, datetimeStart time.Time, timeZone string, duration time.Duration,
+	linkToVideo string, location gorm_models.EventLocation) error {
+	var event gorm_models.Event
+	if err := g.WithContext(ctx).First(&event, idEvent).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errNoEvent
+		}
+		return errInternal
+	}
+
+	for _, groupID := range []int64{event.IDGroup, idGroup} {
+		isAdmin, err := g.isAdmin(ctx, chatID, groupID)
+		if err != nil {
+			return err
+		}
+		if !isAdmin {
+			return fmt.Errorf("только администратор может редактировать события")
+		}
+	}
+
+	exists, err := g.categoryExists(ctx, idGroup, category)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return errNoCategory
+	}
+
+	return g.WithContext(ctx).Model(&event).Select(
+		"NameEvent", "IDGroup", "DatetimeStart", "TimeZone", "Category", "Duration", "IsAllDay", "LinkToVideo",
+		"LocationTitle", "LocationAddress", "Latitude", "Longitude",
+	).Updates(gorm_models.Event{
+		NameEvent:     nameEvent,
+		IDGroup:       idGroup,
+		DatetimeStart: datetimeStart,
+		TimeZone:      timeZone,
+		Category:      category,
+		Duration:      duration,
+		IsAllDay:      isAllDay,
+		LinkToVideo:   linkToVideo,
+		EventLocation: location,
+	}).Error
+}
+
+// DeleteEvent переносит событие с указанным именем в корзину.
 // Проверяется, что пользователь является администратором группы.
 func (g *GormProvider) DeleteEvent(ctx context.Context, chatID int64, nameEvent string) error {
 	var event gorm_models.Event
@@ -243,11 +348,82 @@ func (g *GormProvider) DeleteEvent(ctx context.Context, chatID int64, nameEvent
 	return g.WithContext(ctx).Delete(&event).Error
 }
 
+// GetAdminGroups возвращает группы, в которых пользователь является администратором.
+func (g *GormProvider) GetAdminGroups(ctx context.Context, chatID int64) ([]gorm_models.Group, error) {
+	var groups []gorm_models.Group
+	if err := g.WithContext(ctx).Where("id_group IN (?)",
+		g.WithContext(ctx).Model(&gorm_models.Membership{}).
+			Select("id_group").
+			Where("is_admin = true AND id_user IN (?)",
+				g.WithContext(ctx).Model(&gorm_models.User{}).Select("id_user").Where("id_chat = ?", chatID)),
+	).Find(&groups).Error; err != nil {
+		return nil, errInternal
+	}
+	return groups, nil
+}
+
+// GetUserGroups возвращает все группы, в которых состоит пользователь.
+func (g *GormProvider) GetUserGroups(ctx context.Context, chatID int64) ([]gorm_models.Group, error) {
+	var groups []gorm_models.Group
+	if err := g.WithContext(ctx).Where("id_group IN (?)",
+		g.WithContext(ctx).Model(&gorm_models.Membership{}).
+			Select("id_group").
+			Where("id_user IN (?)",
+				g.WithContext(ctx).Model(&gorm_models.User{}).Select("id_user").Where("id_chat = ?", chatID)),
+	).Order("group_name").Find(&groups).Error; err != nil {
+		return nil, errInternal
+	}
+	return groups, nil
+}
+
+// IsGroupAdmin сообщает, является ли пользователь администратором указанной группы.
+func (g *GormProvider) IsGroupAdmin(ctx context.Context, chatID int64, groupID int64) (bool, error) {
+	return g.isAdmin(ctx, chatID, groupID)
+}
+
+// CheckGroupMember возвращает ошибку, если пользователь не состоит в указанной группе.
+func (g *GormProvider) CheckGroupMember(ctx context.Context, chatID int64, groupID int64) error {
+	_, err := g.groupMember(ctx, chatID, groupID)
+	return err
+}
+
+// userByChatID возвращает пользователя по chatID.
+func (g *GormProvider) userByChatID(ctx context.Context, chatID int64) (gorm_models.User, error) {
+	var user gorm_models.User
+	if err := g.WithContext(ctx).Where("id_chat = ?", chatID).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return user, errNoUser
+		}
+		return user, errInternal
+	}
+	return user, nil
+}
+
+// groupMember возвращает пользователя, если он состоит в указанной группе.
+func (g *GormProvider) groupMember(ctx context.Context, chatID int64, groupID int64) (gorm_models.User, error) {
+	user, err := g.userByChatID(ctx, chatID)
+	if err != nil {
+		return user, err
+	}
+
+	var count int64
+	if err = g.WithContext(ctx).Model(&gorm_models.Membership{}).
+		Where("id_group = ? AND id_user = ?", groupID, user.IDUser).
+		Count(&count).Error; err != nil {
+		return user, errInternal
+	}
+	if count == 0 {
+		return user, fmt.Errorf("вы не состоите в этой группе")
+	}
+	return user, nil
+}
+
 // isAdmin проверяет, является ли пользователь администратором указанной группы.
 func (g *GormProvider) isAdmin(ctx context.Context, chatID int64, groupID int64) (bool, error) {
 	var membership gorm_models.Membership
 	if err := g.WithContext(ctx).
-		Where("id_group = ? AND id_user = ? AND id_admin = true", groupID, chatID).
+		Where("id_group = ? AND is_admin = true AND id_user IN (?)", groupID,
+			g.WithContext(ctx).Model(&gorm_models.User{}).Select("id_user").Where("id_chat = ?", chatID)).
 		First(&membership).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return false, nil
@@ -0,0 +1,124 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"aliorToDoBot/src/db/gorm_models"
+)
+
+type providerDigest interface {
+	GetDigestSetting(ChatID int64) (gorm_models.DigestSetting, error)
+	SaveDigestSetting(ChatID int64, Setting gorm_models.DigestSetting) error
+	GetEnabledDigests() ([]gorm_models.DigestSetting, error)
+	MarkDigestSent(IDUser int64, Day string) (bool, error)
+	UnmarkDigestSent(IDUser int64, Day string, Previous *time.Time) error
+	GetOverdueTasks(ChatID int64, Now time.Time) ([]gorm_models.Task, error)
+}
+
+// GetDigestSetting возвращает настройку ежедневной сводки пользователя.
+// Если пользователь её не менял, возвращаются значения по умолчанию: сводка выключена.
+func (g *GormProvider) GetDigestSetting(ctx context.Context, chatID int64) (gorm_models.DigestSetting, error) {
+	user, err := g.userByChatID(ctx, chatID)
+	if err != nil {
+		return gorm_models.DigestSetting{}, err
+	}
+
+	setting := gorm_models.DigestSetting{
+		IDUser:    user.IDUser,
+		User:      user,
+		SendHour:  gorm_models.DefaultDigestHour,
+		SkipEmpty: true,
+	}
+	if err = g.WithContext(ctx).Where("id_user = ?", user.IDUser).First(&setting).Error; err != nil &&
+		!errors.Is(err, gorm.ErrRecordNotFound) {
+		return setting, errInternal
+	}
+	return setting, nil
+}
+
+// SaveDigestSetting сохраняет включение, время отправки и показ пустых дней ежедневной сводки.
+func (g *GormProvider) SaveDigestSetting(ctx context.Context, chatID int64, setting gorm_models.DigestSetting) error {
+	if setting.SendHour < 0 || setting.SendHour > 23 || setting.SendMinute < 0 || setting.SendMinute > 59 {
+		return fmt.Errorf("некорректное время отправки сводки")
+	}
+	user, err := g.userByChatID(ctx, chatID)
+	if err != nil {
+		return err
+	}
+	setting.IDUser = user.IDUser
+
+	if err = g.WithContext(ctx).Omit("User", "LastSentOn").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "id_user"}},
+		DoUpdates: clause.AssignmentColumns([]string{"enabled", "send_hour", "send_minute", "skip_empty"}),
+	}).Create(&setting).Error; err != nil {
+		return errInternal
+	}
+	return nil
+}
+
+// GetEnabledDigests возвращает включённые сводки вместе с пользователями.
+func (g *GormProvider) GetEnabledDigests(ctx context.Context) ([]gorm_models.DigestSetting, error) {
+	var settings []gorm_models.DigestSetting
+	if err := g.WithContext(ctx).Preload("User").Where("enabled = true").Find(&settings).Error; err != nil {
+		return nil, errInternal
+	}
+	return settings, nil
+}
+
+// MarkDigestSent отмечает, что сводка за день day (в формате 2006-01-02) отправлена пользователю.
+// Возвращает false, если сводка за этот день уже была отмечена: так она не уйдёт дважды.
+func (g *GormProvider) MarkDigestSent(ctx context.Context, idUser int64, day string) (bool, error) {
+	result := g.WithContext(ctx).Model(&gorm_models.DigestSetting{}).
+		Where("id_user = ? AND (last_sent_on IS NULL OR last_sent_on < ?)", idUser, day).
+		Update("last_sent_on", day)
+	if result.Error != nil {
+		return false, errInternal
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// UnmarkDigestSent возвращает день последней сводки к previous, чтобы сводка за day была отправлена повторно.
+// Используется, если сформировать или доставить сообщение не удалось.
+func (g *GormProvider) UnmarkDigestSent(ctx context.Context, idUser int64, day string, previous *time.Time) error {
+	var previousDay *string
+	if previous != nil {
+		formatted := previous.Format("2006-01-02")
+		previousDay = &formatted
+	}
+	if err := g.WithContext(ctx).Model(&gorm_models.DigestSetting{}).
+		Where("id_user = ? AND last_sent_on = ?", idUser, day).
+		Update("last_sent_on", previousDay).Error; err != nil {
+		return errInternal
+	}
+	return nil
+}
+
+// GetOverdueTasks возвращает невыполненные задачи групп пользователя со сроком раньше now,
+// которые назначены пользователю или ещё никому не назначены.
+func (g *GormProvider) GetOverdueTasks(ctx context.Context, chatID int64, now time.Time) ([]gorm_models.Task, error) {
+	user, err := g.userByChatID(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []gorm_models.Task
+	if err = g.WithContext(ctx).Preload("Assignees.User").
+		Where("is_done = false AND deadline < ?", now).
+		Where("id_group IN (?)", g.WithContext(ctx).Model(&gorm_models.Membership{}).
+			Select("id_group").
+			Where("id_user = ?", user.IDUser)).
+		Where("id_task IN (?) OR id_task NOT IN (?)",
+			g.WithContext(ctx).Model(&gorm_models.TaskAssignee{}).Select("id_task").Where("id_user = ?", user.IDUser),
+			g.WithContext(ctx).Model(&gorm_models.TaskAssignee{}).Select("id_task")).
+		Order(taskOrder).
+		Find(&tasks).Error; err != nil {
+		return nil, errInternal
+	}
+	return tasks, nil
+}
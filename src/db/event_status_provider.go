@@ -0,0 +1,85 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"aliorToDoBot/src/db/gorm_models"
+)
+
+type providerEventStatus interface {
+	CancelEvent(IDEvent int64) error
+	PostponeEvent(IDEvent int64, NewStart time.Time) error
+	FinishEvent(IDEvent int64) error
+	RestoreEventStatus(IDEvent int64) error
+}
+
+// CancelEvent отменяет мероприятие. Для повторяющегося мероприятия отменяются все повторения.
+func (g *GormProvider) CancelEvent(ctx context.Context, chatID int64, idEvent int64) error {
+	return g.setManualStatus(ctx, chatID, idEvent, map[string]interface{}{
+		"status":        gorm_models.EventStatusCancelled,
+		"status_manual": true,
+	})
+}
+
+// PostponeEvent переносит мероприятие на новое время.
+// Статус «Перенесено» сохраняется, пока мероприятие не начнётся.
+// Повторяющиеся мероприятия переносятся по одному повторению.
+func (g *GormProvider) PostponeEvent(ctx context.Context, chatID int64, idEvent int64, newStart time.Time) error {
+	event, err := g.eventByID(ctx, idEvent)
+	if err != nil {
+		return err
+	}
+	if event.RecurFreq != "" {
+		return fmt.Errorf("у повторяющегося мероприятия можно перенести только отдельное повторение")
+	}
+	if !newStart.After(time.Now()) {
+		return fmt.Errorf("новое время должно быть в будущем")
+	}
+
+	return g.setManualStatus(ctx, chatID, idEvent, map[string]interface{}{
+		"datetime_start": newStart,
+		"status":         gorm_models.EventStatusPostponed,
+		"status_manual":  true,
+	})
+}
+
+// FinishEvent досрочно завершает мероприятие.
+func (g *GormProvider) FinishEvent(ctx context.Context, chatID int64, idEvent int64) error {
+	return g.setManualStatus(ctx, chatID, idEvent, map[string]interface{}{
+		"status":        gorm_models.EventStatusFinished,
+		"status_manual": true,
+	})
+}
+
+// RestoreEventStatus снимает выставленный вручную статус.
+// Статус будет снова вычисляться по времени мероприятия.
+func (g *GormProvider) RestoreEventStatus(ctx context.Context, chatID int64, idEvent int64) error {
+	return g.setManualStatus(ctx, chatID, idEvent, map[string]interface{}{
+		"status":        gorm_models.EventStatusPlanned,
+		"status_manual": false,
+	})
+}
+
+// setManualStatus обновляет статус мероприятия.
+// Менять статус вручную может только администратор группы мероприятия.
+func (g *GormProvider) setManualStatus(ctx context.Context, chatID int64, idEvent int64, updates map[string]interface{}) error {
+	event, err := g.eventByID(ctx, idEvent)
+	if err != nil {
+		return err
+	}
+
+	isAdmin, err := g.isAdmin(ctx, chatID, event.IDGroup)
+	if err != nil {
+		return err
+	}
+	if !isAdmin {
+		return fmt.Errorf("только администратор может менять статус мероприятия")
+	}
+
+	if err = g.WithContext(ctx).Model(&event).Updates(updates).Error; err != nil {
+		return errInternal
+	}
+	return nil
+}
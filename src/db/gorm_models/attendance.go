@@ -0,0 +1,22 @@
+package gorm_models
+
+import (
+	"time"
+)
+
+// Ответы участника на приглашение на мероприятие
+const (
+	AttendanceGoing    = "going"
+	AttendanceNotGoing = "not_going"
+	AttendanceMaybe    = "maybe"
+)
+
+// Attendance хранит ответ участника группы, придёт ли он на повторение мероприятия.
+// OccurrenceStart — исходное начало повторения, для неповторяющегося мероприятия — его начало.
+type Attendance struct {
+	IDEvent         int64     `gorm:"column:id_event;not null;uniqueIndex:idx_attendance"`
+	OccurrenceStart time.Time `gorm:"column:occurrence_start;type:timestamp with time zone;not null;uniqueIndex:idx_attendance"`
+	IDUser          int64     `gorm:"column:id_user;not null;uniqueIndex:idx_attendance"`
+	Status          string    `gorm:"column:status;not null;check:status IN ('going', 'not_going', 'maybe')"`
+	UpdatedAt       time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
@@ -0,0 +1,16 @@
+package gorm_models
+
+// Category категория мероприятий, которую администраторы задают для своей группы
+type Category struct {
+	IDCategory int64  `gorm:"column:id_category;primaryKey;autoIncrement"`
+	IDGroup    int64  `gorm:"column:id_group;not null;uniqueIndex:idx_group_category"`
+	Name       string `gorm:"column:name;type:text;not null;uniqueIndex:idx_group_category"`
+	Emoji      string `gorm:"column:emoji;type:text;not null;default:''"`
+}
+
+// DefaultCategories категории, которые получает каждая новая группа
+var DefaultCategories = []Category{
+	{Name: "Личное", Emoji: "🙂"},
+	{Name: "Семья", Emoji: "👨‍👩‍👧"},
+	{Name: "Работа", Emoji: "💼"},
+}
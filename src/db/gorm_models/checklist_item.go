@@ -0,0 +1,16 @@
+package gorm_models
+
+import (
+	"time"
+)
+
+// ChecklistItem пункт чек-листа мероприятия. IDCheckedBy и CheckedAt заполнены,
+// когда пункт отмечен выполненным
+type ChecklistItem struct {
+	IDItem      int64      `gorm:"column:id_item;primaryKey;autoIncrement"`
+	IDEvent     int64      `gorm:"column:id_event;not null;index"`
+	Text        string     `gorm:"column:text;type:text;not null"`
+	IsDone      bool       `gorm:"column:is_done;not null;default:false"`
+	IDCheckedBy *int64     `gorm:"column:id_checked_by"`
+	CheckedAt   *time.Time `gorm:"column:checked_at;type:timestamp with time zone"`
+}
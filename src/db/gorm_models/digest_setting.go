@@ -0,0 +1,23 @@
+package gorm_models
+
+import (
+	"time"
+)
+
+// DefaultDigestHour час отправки ежедневной сводки, пока пользователь не выбрал свой
+const DefaultDigestHour = 8
+
+// DigestSetting настройка ежедневной сводки пользователя. Время отправки задаётся
+// в часовом поясе пользователя. LastSentOn — день последней отправленной сводки,
+// чтобы сводка уходила не чаще раза в день.
+// У полей нет значений по умолчанию в тегах: GORM подставил бы их вместо нулевых
+// значений (00 часов, показ пустых дней). Значения по умолчанию задаёт GetDigestSetting.
+type DigestSetting struct {
+	IDUser     int64      `gorm:"column:id_user;primaryKey"`
+	User       User       `gorm:"foreignKey:IDUser;references:IDUser"`
+	Enabled    bool       `gorm:"column:enabled;not null"`
+	SendHour   int        `gorm:"column:send_hour;not null"`
+	SendMinute int        `gorm:"column:send_minute;not null"`
+	SkipEmpty  bool       `gorm:"column:skip_empty;not null"`
+	LastSentOn *time.Time `gorm:"column:last_sent_on;type:date"`
+}
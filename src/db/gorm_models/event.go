@@ -2,15 +2,41 @@ package gorm_models
 
 import (
 	"time"
+
+	"gorm.io/gorm"
+)
+
+// Статусы мероприятия. Первые три вычисляются по времени. Отменить, перенести или досрочно
+// завершить мероприятие можно вручную, тогда выставляется StatusManual и статус не пересчитывается
+const (
+	EventStatusPlanned    = "Запланировано"
+	EventStatusInProgress = "В процессе"
+	EventStatusFinished   = "Завершено"
+	EventStatusCancelled  = "Отменено"
+	EventStatusPostponed  = "Перенесено"
 )
 
 type Event struct {
 	IDEvent       int64         `gorm:"primaryKey;autoIncrement"`
 	NameEvent     string        `gorm:"not null"`
 	IDGroup       int64         `gorm:"foreignKey:IDGroup;references:IDGroup;not null"`
-	DatetimeStart time.Time     `gorm:"type:timestamp without time zone;column:datetime_start"`
-	Category      string        `gorm:"not null;check:category IN ('Личное','Семья','Работа')"`
+	DatetimeStart time.Time     `gorm:"type:timestamp with time zone;column:datetime_start"`
+	Category      string        `gorm:"not null"`
 	Duration      time.Duration `gorm:"column:duration"`
 	IsAllDay      bool          `gorm:"not null"`
-	Status        string        `gorm:"not null; check:status IN ('Запланировано', 'В процессе', 'Завершено')"`
+	TimeZone      string        `gorm:"column:time_zone;type:text;not null;default:'Europe/Moscow'"`
+	LinkToVideo   string        `gorm:"column:link_to_video;type:text;not null;default:''"`
+	Status        string        `gorm:"not null; check:status IN ('Запланировано', 'В процессе', 'Завершено', 'Отменено', 'Перенесено')"`
+	StatusManual  bool          `gorm:"column:status_manual;not null;default:false"`
+	RecurFreq     string        `gorm:"column:recur_freq;not null;default:'';check:recur_freq IN ('', 'daily', 'weekly', 'monthly')"`
+	RecurInterval int           `gorm:"column:recur_interval;not null;default:1"`
+	RecurWeekdays string        `gorm:"column:recur_weekdays;not null;default:''"`
+	RecurUntil    *time.Time    `gorm:"column:recur_until;type:timestamp with time zone"`
+	RecurCount    int           `gorm:"column:recur_count;not null;default:0"`
+	EventLocation `gorm:"embedded"`
+	DeletedAt     gorm.DeletedAt `gorm:"column:deleted_at;type:timestamp with time zone;index"`
+
+	Checklist   []ChecklistItem   `gorm:"foreignKey:IDEvent;constraint:OnDelete:CASCADE"`
+	Comments    []EventComment    `gorm:"foreignKey:IDEvent;constraint:OnDelete:CASCADE"`
+	Attachments []EventAttachment `gorm:"foreignKey:IDEvent;constraint:OnDelete:CASCADE"`
 }
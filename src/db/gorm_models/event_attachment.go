@@ -0,0 +1,27 @@
+package gorm_models
+
+import (
+	"time"
+)
+
+// Виды вложений мероприятия
+const (
+	AttachmentDocument = "document"
+	AttachmentPhoto    = "photo"
+)
+
+// EventAttachment файл, прикреплённый к мероприятию. Сам файл хранится в Telegram,
+// бот сохраняет только его FileID и описание
+type EventAttachment struct {
+	IDAttachment int64     `gorm:"column:id_attachment;primaryKey;autoIncrement"`
+	IDEvent      int64     `gorm:"column:id_event;not null;uniqueIndex:idx_event_attachment"`
+	IDUser       int64     `gorm:"column:id_user;not null"`
+	Kind         string    `gorm:"column:kind;type:text;not null;check:kind IN ('document', 'photo')"`
+	FileID       string    `gorm:"column:file_id;type:text;not null"`
+	FileUniqueID string    `gorm:"column:file_unique_id;type:text;not null;uniqueIndex:idx_event_attachment"`
+	FileName     string    `gorm:"column:file_name;type:text;not null;default:''"`
+	MimeType     string    `gorm:"column:mime_type;type:text;not null;default:''"`
+	FileSize     int64     `gorm:"column:file_size;not null;default:0"`
+	Caption      string    `gorm:"column:caption;type:text;not null;default:''"`
+	CreatedAt    time.Time `gorm:"column:created_at;type:timestamp with time zone;not null"`
+}
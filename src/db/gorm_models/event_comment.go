@@ -0,0 +1,15 @@
+package gorm_models
+
+import (
+	"time"
+)
+
+// EventComment комментарий участника группы к мероприятию
+type EventComment struct {
+	IDComment int64     `gorm:"column:id_comment;primaryKey;autoIncrement"`
+	IDEvent   int64     `gorm:"column:id_event;not null;index"`
+	IDUser    int64     `gorm:"column:id_user;not null"`
+	User      User      `gorm:"foreignKey:IDUser;references:IDUser"`
+	Text      string    `gorm:"column:text;type:text;not null"`
+	CreatedAt time.Time `gorm:"column:created_at;type:timestamp with time zone;not null"`
+}
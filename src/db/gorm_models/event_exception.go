@@ -0,0 +1,15 @@
+package gorm_models
+
+import (
+	"time"
+)
+
+// EventException хранит пропуск или перенос одного повторения регулярного мероприятия
+type EventException struct {
+	IDException     int64          `gorm:"primaryKey;autoIncrement"`
+	IDEvent         int64          `gorm:"column:id_event;not null;uniqueIndex:idx_event_occurrence"`
+	OccurrenceStart time.Time      `gorm:"column:occurrence_start;type:timestamp with time zone;not null;uniqueIndex:idx_event_occurrence"`
+	IsSkipped       bool           `gorm:"column:is_skipped;not null"`
+	NewStart        *time.Time     `gorm:"column:new_start;type:timestamp with time zone"`
+	NewDuration     *time.Duration `gorm:"column:new_duration"`
+}
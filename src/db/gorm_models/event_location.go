@@ -0,0 +1,20 @@
+package gorm_models
+
+// EventLocation место проведения мероприятия. Координаты заполнены, если место
+// прислано геопозицией или точкой на карте; адрес, введённый текстом, хранится в LocationTitle
+type EventLocation struct {
+	LocationTitle   string   `gorm:"column:location_title;type:text;not null;default:''"`
+	LocationAddress string   `gorm:"column:location_address;type:text;not null;default:''"`
+	Latitude        *float64 `gorm:"column:latitude"`
+	Longitude       *float64 `gorm:"column:longitude"`
+}
+
+// HasCoordinates сообщает, что место можно открыть на карте
+func (l EventLocation) HasCoordinates() bool {
+	return l.Latitude != nil && l.Longitude != nil
+}
+
+// IsEmpty сообщает, что место не указано
+func (l EventLocation) IsEmpty() bool {
+	return l.LocationTitle == "" && l.LocationAddress == "" && !l.HasCoordinates()
+}
@@ -0,0 +1,22 @@
+package gorm_models
+
+import (
+	"time"
+)
+
+// EventTemplate заготовка мероприятия. Личный шаблон (IDUser задан) виден только владельцу,
+// шаблон без IDUser доступен всем участникам группы IDGroup
+type EventTemplate struct {
+	IDTemplate    int64         `gorm:"column:id_template;primaryKey;autoIncrement"`
+	Name          string        `gorm:"column:name;type:text;not null"`
+	IDGroup       int64         `gorm:"column:id_group;not null;index"`
+	IDUser        *int64        `gorm:"column:id_user"`
+	IDCreator     int64         `gorm:"column:id_creator;not null"`
+	NameEvent     string        `gorm:"column:name_event;not null"`
+	Category      string        `gorm:"column:category;not null"`
+	Duration      time.Duration `gorm:"column:duration;not null;default:0"`
+	IsAllDay      bool          `gorm:"column:is_all_day;not null;default:false"`
+	LinkToVideo   string        `gorm:"column:link_to_video;type:text;not null;default:''"`
+	CreatedAt     time.Time     `gorm:"column:created_at;type:timestamp with time zone;not null"`
+	EventLocation `gorm:"embedded"`
+}
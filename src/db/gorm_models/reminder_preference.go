@@ -0,0 +1,12 @@
+package gorm_models
+
+// ReminderPreference хранит интервалы напоминаний в формате "1d,1h,10m".
+// Заполненный IDEvent задаёт настройку мероприятия, иначе это настройка пользователя:
+// по умолчанию (пустая Category) или для конкретной категории.
+type ReminderPreference struct {
+	IDPreference int64  `gorm:"primaryKey;autoIncrement"`
+	IDUser       *int64 `gorm:"column:id_user;index"`
+	Category     string `gorm:"column:category;not null;default:''"`
+	IDEvent      *int64 `gorm:"column:id_event;index"`
+	Offsets      string `gorm:"column:offsets;not null"`
+}
@@ -0,0 +1,15 @@
+package gorm_models
+
+import (
+	"time"
+)
+
+// SentReminder фиксирует отправленное участнику напоминание о повторении мероприятия
+// за указанный интервал, чтобы каждое напоминание уходило ровно один раз
+type SentReminder struct {
+	IDEvent         int64         `gorm:"column:id_event;not null;uniqueIndex:idx_sent_reminder"`
+	OccurrenceStart time.Time     `gorm:"column:occurrence_start;type:timestamp with time zone;not null;uniqueIndex:idx_sent_reminder"`
+	IDUser          int64         `gorm:"column:id_user;not null;uniqueIndex:idx_sent_reminder"`
+	Offset          time.Duration `gorm:"column:reminder_offset;not null;default:0;uniqueIndex:idx_sent_reminder"`
+	SentAt          time.Time     `gorm:"column:sent_at;autoCreateTime"`
+}
@@ -0,0 +1,29 @@
+package gorm_models
+
+import (
+	"time"
+)
+
+// Приоритеты задач
+const (
+	TaskPriorityHigh   = "high"
+	TaskPriorityNormal = "normal"
+	TaskPriorityLow    = "low"
+)
+
+// Task задача группы без привязки ко времени: со сроком или без него.
+// IsDone совпадает с признаком колонки доски, в которой лежит задача
+type Task struct {
+	IDTask    int64      `gorm:"column:id_task;primaryKey;autoIncrement"`
+	IDGroup   int64      `gorm:"column:id_group;not null;index"`
+	Title     string     `gorm:"column:title;type:text;not null"`
+	Priority  string     `gorm:"column:priority;not null;default:'normal';check:priority IN ('high', 'normal', 'low')"`
+	Deadline  *time.Time `gorm:"column:deadline;type:timestamp with time zone"`
+	IDColumn  *int64     `gorm:"column:id_column;index"`
+	IsDone    bool       `gorm:"column:is_done;not null;default:false"`
+	DoneAt    *time.Time `gorm:"column:done_at;type:timestamp with time zone"`
+	IDCreator int64      `gorm:"column:id_creator;not null"`
+	CreatedAt time.Time  `gorm:"column:created_at;autoCreateTime"`
+
+	Assignees []TaskAssignee `gorm:"foreignKey:IDTask;constraint:OnDelete:CASCADE"`
+}
@@ -0,0 +1,8 @@
+package gorm_models
+
+// TaskAssignee назначает задачу участнику группы
+type TaskAssignee struct {
+	IDTask int64 `gorm:"column:id_task;not null;uniqueIndex:idx_task_assignee"`
+	IDUser int64 `gorm:"column:id_user;not null;uniqueIndex:idx_task_assignee"`
+	User   User  `gorm:"foreignKey:IDUser;references:IDUser"`
+}
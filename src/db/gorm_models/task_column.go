@@ -0,0 +1,17 @@
+package gorm_models
+
+// TaskColumn колонка доски задач группы. Задачи в колонке с IsDone считаются выполненными
+type TaskColumn struct {
+	IDColumn int64  `gorm:"column:id_column;primaryKey;autoIncrement"`
+	IDGroup  int64  `gorm:"column:id_group;not null;uniqueIndex:idx_group_task_column"`
+	Name     string `gorm:"column:name;type:text;not null;uniqueIndex:idx_group_task_column"`
+	Position int    `gorm:"column:position;not null;default:0"`
+	IsDone   bool   `gorm:"column:is_done;not null;default:false"`
+}
+
+// DefaultTaskColumns колонки, которые получает доска каждой новой группы
+var DefaultTaskColumns = []TaskColumn{
+	{Name: "К выполнению", Position: 1},
+	{Name: "В работе", Position: 2},
+	{Name: "Готово", Position: 3, IsDone: true},
+}
@@ -1,7 +1,11 @@
 package gorm_models
 
+// DefaultTimeZone часовой пояс пользователя, пока он не выбрал свой
+const DefaultTimeZone = "Europe/Moscow"
+
 type User struct {
 	IDUser   int64  `gorm:"column:id_user;primaryKey"`
 	UserName string `gorm:"column:user_name;type:text;not null"`
 	IDChat   int64  `gorm:"column:id_chat;autoIncrement"`
+	TimeZone string `gorm:"column:time_zone;type:text;not null;default:'Europe/Moscow'"`
 }
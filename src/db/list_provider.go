@@ -0,0 +1,72 @@
+package db
+
+import (
+	"context"
+
+	"aliorToDoBot/src/db/gorm_models"
+)
+
+type providerList interface {
+	GetUserEventsPage(ChatID int64, UpcomingOnly bool, Limit int, Offset int) ([]gorm_models.Event, int64, error)
+	GetUserGroupsPage(ChatID int64, Limit int, Offset int) ([]gorm_models.Group, int64, error)
+}
+
+// GetUserEventsPage возвращает страницу мероприятий из групп пользователя в порядке начала
+// и общее число мероприятий. Если upcomingOnly, завершённые и отменённые мероприятия не учитываются.
+func (g *GormProvider) GetUserEventsPage(ctx context.Context, chatID int64, upcomingOnly bool, limit, offset int) ([]gorm_models.Event, int64, error) {
+	user, err := g.userByChatID(ctx, chatID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := g.WithContext(ctx).Model(&gorm_models.Event{}).
+		Where("id_group IN (?)", g.WithContext(ctx).Model(&gorm_models.Membership{}).
+			Select("id_group").
+			Where("id_user = ?", user.IDUser))
+	if upcomingOnly {
+		query = query.Where("status NOT IN ?",
+			[]string{gorm_models.EventStatusFinished, gorm_models.EventStatusCancelled})
+	}
+
+	var total int64
+	if err = query.Count(&total).Error; err != nil {
+		return nil, 0, errInternal
+	}
+
+	var events []gorm_models.Event
+	if err = query.Preload("Checklist").
+		Order("datetime_start, id_event").
+		Limit(limit).
+		Offset(offset).
+		Find(&events).Error; err != nil {
+		return nil, 0, errInternal
+	}
+	return events, total, nil
+}
+
+// GetUserGroupsPage возвращает страницу групп пользователя в порядке названия и общее число групп.
+func (g *GormProvider) GetUserGroupsPage(ctx context.Context, chatID int64, limit, offset int) ([]gorm_models.Group, int64, error) {
+	user, err := g.userByChatID(ctx, chatID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := g.WithContext(ctx).Model(&gorm_models.Group{}).
+		Where("id_group IN (?)", g.WithContext(ctx).Model(&gorm_models.Membership{}).
+			Select("id_group").
+			Where("id_user = ?", user.IDUser))
+
+	var total int64
+	if err = query.Count(&total).Error; err != nil {
+		return nil, 0, errInternal
+	}
+
+	var groups []gorm_models.Group
+	if err = query.Order("group_name, id_group").
+		Limit(limit).
+		Offset(offset).
+		Find(&groups).Error; err != nil {
+		return nil, 0, errInternal
+	}
+	return groups, total, nil
+}
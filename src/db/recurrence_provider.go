@@ -0,0 +1,77 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"aliorToDoBot/src/db/gorm_models"
+)
+
+type providerRecurrence interface {
+	GetEventExceptions(IDEvents []int64) ([]gorm_models.EventException, error)
+	SkipOccurrence(IDEvent int64, OccurrenceStart time.Time) error
+	MoveOccurrence(IDEvent int64, OccurrenceStart time.Time, NewStart time.Time) error
+}
+
+// GetEventExceptions возвращает пропуски и переносы повторений для указанных мероприятий.
+func (g *GormProvider) GetEventExceptions(ctx context.Context, eventIDs []int64) ([]gorm_models.EventException, error) {
+	var exceptions []gorm_models.EventException
+	if len(eventIDs) == 0 {
+		return exceptions, nil
+	}
+	if err := g.WithContext(ctx).Where("id_event IN ?", eventIDs).Find(&exceptions).Error; err != nil {
+		return nil, errInternal
+	}
+	return exceptions, nil
+}
+
+// SkipOccurrence отменяет одно повторение регулярного мероприятия.
+func (g *GormProvider) SkipOccurrence(ctx context.Context, chatID int64, idEvent int64, occurrenceStart time.Time) error {
+	return g.saveException(ctx, chatID, idEvent, gorm_models.EventException{
+		IDEvent:         idEvent,
+		OccurrenceStart: occurrenceStart,
+		IsSkipped:       true,
+	})
+}
+
+// MoveOccurrence переносит одно повторение регулярного мероприятия на новое время.
+func (g *GormProvider) MoveOccurrence(ctx context.Context, chatID int64, idEvent int64, occurrenceStart, newStart time.Time) error {
+	return g.saveException(ctx, chatID, idEvent, gorm_models.EventException{
+		IDEvent:         idEvent,
+		OccurrenceStart: occurrenceStart,
+		NewStart:        &newStart,
+	})
+}
+
+// saveException сохраняет исключение для повторения, заменяя существующее.
+// Изменять повторения может только администратор группы мероприятия.
+func (g *GormProvider) saveException(ctx context.Context, chatID int64, idEvent int64, exception gorm_models.EventException) error {
+	var event gorm_models.Event
+	if err := g.WithContext(ctx).First(&event, idEvent).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errNoEvent
+		}
+		return errInternal
+	}
+	if event.RecurFreq == "" {
+		return fmt.Errorf("мероприятие не является повторяющимся")
+	}
+
+	isAdmin, err := g.isAdmin(ctx, chatID, event.IDGroup)
+	if err != nil {
+		return err
+	}
+	if !isAdmin {
+		return fmt.Errorf("только администратор может изменять повторения")
+	}
+
+	return g.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "id_event"}, {Name: "occurrence_start"}},
+		DoUpdates: clause.AssignmentColumns([]string{"is_skipped", "new_start", "new_duration"}),
+	}).Create(&exception).Error
+}
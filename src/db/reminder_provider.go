@@ -0,0 +1,156 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"aliorToDoBot/src/db/gorm_models"
+)
+
+type providerReminder interface {
+	GetReminderCandidates(From time.Time, To time.Time) ([]gorm_models.Event, error)
+	GetGroupMembers(IDGroup int64) ([]gorm_models.User, error)
+	MarkReminderSent(IDEvent int64, OccurrenceStart time.Time, IDUser int64, Offset time.Duration) (bool, error)
+	UnmarkReminderSent(IDEvent int64, OccurrenceStart time.Time, IDUser int64, Offset time.Duration) error
+	GetReminderPreferences(IDEvents []int64, IDUsers []int64) ([]gorm_models.ReminderPreference, error)
+	GetUserReminderPreferences(ChatID int64) ([]gorm_models.ReminderPreference, error)
+	SetUserReminderOffsets(ChatID int64, Category string, Offsets string) error
+	SetEventReminderOffsets(IDEvent int64, Offsets string) error
+}
+
+// GetReminderCandidates возвращает мероприятия, для которых в интервале [from, to]
+// может понадобиться напоминание: начинающиеся в нём и все повторяющиеся.
+// Отменённые и досрочно завершённые мероприятия пропускаются.
+func (g *GormProvider) GetReminderCandidates(ctx context.Context, from, to time.Time) ([]gorm_models.Event, error) {
+	var events []gorm_models.Event
+	if err := g.WithContext(ctx).Preload("Checklist").
+		Where("recur_freq <> '' OR datetime_start BETWEEN ? AND ?", from, to).
+		Where("NOT (status_manual AND status IN ?)",
+			[]string{gorm_models.EventStatusCancelled, gorm_models.EventStatusFinished}).
+		Find(&events).Error; err != nil {
+		return nil, errInternal
+	}
+	return events, nil
+}
+
+// GetGroupMembers возвращает всех участников группы.
+func (g *GormProvider) GetGroupMembers(ctx context.Context, idGroup int64) ([]gorm_models.User, error) {
+	var users []gorm_models.User
+	if err := g.WithContext(ctx).Where("id_user IN (?)",
+		g.WithContext(ctx).Model(&gorm_models.Membership{}).
+			Select("id_user").
+			Where("id_group = ?", idGroup),
+	).Find(&users).Error; err != nil {
+		return nil, errInternal
+	}
+	return users, nil
+}
+
+// MarkReminderSent отмечает напоминание за указанный интервал как отправленное.
+// Возвращает false, если напоминание уже было отмечено ранее.
+func (g *GormProvider) MarkReminderSent(ctx context.Context, idEvent int64, occurrenceStart time.Time, idUser int64,
+	offset time.Duration) (bool, error) {
+	tx := g.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&gorm_models.SentReminder{
+		IDEvent:         idEvent,
+		OccurrenceStart: occurrenceStart,
+		IDUser:          idUser,
+		Offset:          offset,
+	})
+	if tx.Error != nil {
+		return false, errInternal
+	}
+	return tx.RowsAffected == 1, nil
+}
+
+// UnmarkReminderSent снимает отметку, чтобы напоминание было отправлено повторно.
+// Используется, если доставить сообщение не удалось.
+func (g *GormProvider) UnmarkReminderSent(ctx context.Context, idEvent int64, occurrenceStart time.Time, idUser int64,
+	offset time.Duration) error {
+	if err := g.WithContext(ctx).
+		Where("id_event = ? AND occurrence_start = ? AND id_user = ? AND reminder_offset = ?",
+			idEvent, occurrenceStart, idUser, offset).
+		Delete(&gorm_models.SentReminder{}).Error; err != nil {
+		return errInternal
+	}
+	return nil
+}
+
+// GetReminderPreferences возвращает настройки напоминаний указанных мероприятий и пользователей.
+func (g *GormProvider) GetReminderPreferences(ctx context.Context, eventIDs []int64, userIDs []int64) ([]gorm_models.ReminderPreference, error) {
+	var preferences []gorm_models.ReminderPreference
+
+	query := g.WithContext(ctx)
+	switch {
+	case len(eventIDs) > 0 && len(userIDs) > 0:
+		query = query.Where("id_event IN ? OR id_user IN ?", eventIDs, userIDs)
+	case len(eventIDs) > 0:
+		query = query.Where("id_event IN ?", eventIDs)
+	case len(userIDs) > 0:
+		query = query.Where("id_user IN ?", userIDs)
+	default:
+		return preferences, nil
+	}
+
+	if err := query.Find(&preferences).Error; err != nil {
+		return nil, errInternal
+	}
+	return preferences, nil
+}
+
+// GetUserReminderPreferences возвращает личные настройки напоминаний пользователя.
+func (g *GormProvider) GetUserReminderPreferences(ctx context.Context, chatID int64) ([]gorm_models.ReminderPreference, error) {
+	user, err := g.userByChatID(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	var preferences []gorm_models.ReminderPreference
+	if err = g.WithContext(ctx).Where("id_user = ?", user.IDUser).Order("category").Find(&preferences).Error; err != nil {
+		return nil, errInternal
+	}
+	return preferences, nil
+}
+
+// SetUserReminderOffsets сохраняет интервалы напоминаний пользователя по умолчанию
+// (пустая категория) или для указанной категории. Пустые offsets удаляют настройку.
+func (g *GormProvider) SetUserReminderOffsets(ctx context.Context, chatID int64, category string, offsets string) error {
+	user, err := g.userByChatID(ctx, chatID)
+	if err != nil {
+		return err
+	}
+
+	return g.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id_user = ? AND category = ? AND id_event IS NULL", user.IDUser, category).
+			Delete(&gorm_models.ReminderPreference{}).Error; err != nil {
+			return errInternal
+		}
+		if offsets == "" {
+			return nil
+		}
+		return tx.Create(&gorm_models.ReminderPreference{
+			IDUser:   &user.IDUser,
+			Category: category,
+			Offsets:  offsets,
+		}).Error
+	})
+}
+
+// SetEventReminderOffsets сохраняет интервалы напоминаний для конкретного мероприятия.
+// Пустые offsets удаляют настройку.
+func (g *GormProvider) SetEventReminderOffsets(ctx context.Context, idEvent int64, offsets string) error {
+	return g.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id_event = ?", idEvent).Delete(&gorm_models.ReminderPreference{}).Error; err != nil {
+			return errInternal
+		}
+		if offsets == "" {
+			return nil
+		}
+		return tx.Create(&gorm_models.ReminderPreference{
+			IDEvent: &idEvent,
+			Offsets: offsets,
+		}).Error
+	})
+}
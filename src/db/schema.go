@@ -0,0 +1,137 @@
+package db
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"aliorToDoBot/src/db/gorm_models"
+)
+
+// legacyTimestamp столбцы модели, в которых время раньше хранилось как московское без часового пояса
+type legacyTimestamp struct {
+	model   interface{}
+	columns []string
+}
+
+var legacyTimestamps = []legacyTimestamp{
+	{model: &gorm_models.Event{}, columns: []string{"datetime_start", "recur_until"}},
+	{model: &gorm_models.EventException{}, columns: []string{"occurrence_start", "new_start"}},
+	{model: &gorm_models.SentReminder{}, columns: []string{"occurrence_start", "sent_at"}},
+}
+
+// ConvertLegacyTimestamps переводит столбцы времени без часового пояса в timestamp with time zone,
+// считая сохранённые значения московскими. Вызывается до AutoMigrate: сам AutoMigrate сменил бы тип
+// без пересчёта, и все мероприятия сдвинулись бы на разницу с часовым поясом сервера базы.
+func ConvertLegacyTimestamps(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, legacy := range legacyTimestamps {
+			if !tx.Migrator().HasTable(legacy.model) {
+				continue
+			}
+			stmt := &gorm.Statement{DB: tx}
+			if err := stmt.Parse(legacy.model); err != nil {
+				return err
+			}
+			columnTypes, err := tx.Migrator().ColumnTypes(legacy.model)
+			if err != nil {
+				return err
+			}
+
+			for _, columnType := range columnTypes {
+				if columnType.DatabaseTypeName() != "timestamp" || !containsColumn(legacy.columns, columnType.Name()) {
+					continue
+				}
+				column := clause.Column{Name: columnType.Name()}
+				if err = tx.Exec("ALTER TABLE ? ALTER COLUMN ? TYPE timestamp with time zone USING ? AT TIME ZONE '"+
+					gorm_models.DefaultTimeZone+"'", clause.Table{Name: stmt.Schema.Table}, column, column).Error; err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func containsColumn(columns []string, name string) bool {
+	for _, column := range columns {
+		if column == name {
+			return true
+		}
+	}
+	return false
+}
+
+// RefreshEventStatusCheck пересоздаёт проверку допустимых статусов мероприятия.
+// AutoMigrate создаёт только отсутствующие ограничения и не изменяет существующие,
+// поэтому в старых базах осталась бы проверка без статусов «Отменено» и «Перенесено».
+func RefreshEventStatusCheck(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if tx.Migrator().HasConstraint(&gorm_models.Event{}, "Status") {
+			if err := tx.Migrator().DropConstraint(&gorm_models.Event{}, "Status"); err != nil {
+				return err
+			}
+		}
+		return tx.Migrator().CreateConstraint(&gorm_models.Event{}, "Status")
+	})
+}
+
+// DropEventCategoryCheck удаляет из старых баз проверку постоянного списка категорий мероприятия.
+// Категории теперь задают группы, а AutoMigrate не удаляет ограничения, которых больше нет в модели.
+func DropEventCategoryCheck(db *gorm.DB) error {
+	if !db.Migrator().HasConstraint(&gorm_models.Event{}, "chk_events_category") {
+		return nil
+	}
+	return db.Migrator().DropConstraint(&gorm_models.Event{}, "chk_events_category")
+}
+
+// BackfillGroupCategories добавляет стандартные категории группам, у которых нет ни одной категории.
+// Это группы, созданные до появления категорий групп: удалить последнюю категорию группы нельзя.
+func BackfillGroupCategories(db *gorm.DB) error {
+	var groupIDs []int64
+	if err := db.Model(&gorm_models.Group{}).
+		Where("NOT EXISTS (?)", db.Model(&gorm_models.Category{}).
+			Select("1").
+			Where("categories.id_group = groups.id_group")).
+		Pluck("id_group", &groupIDs).Error; err != nil {
+		return err
+	}
+	if len(groupIDs) == 0 {
+		return nil
+	}
+
+	categories := make([]gorm_models.Category, 0, len(groupIDs)*len(gorm_models.DefaultCategories))
+	for _, idGroup := range groupIDs {
+		for _, category := range gorm_models.DefaultCategories {
+			category.IDGroup = idGroup
+			categories = append(categories, category)
+		}
+	}
+	return db.Create(&categories).Error
+}
+
+// AddAttendanceOccurrence добавляет к ответам участников начало повторения мероприятия.
+// Прежние ответы относятся к первому повторению. Вызывается до AutoMigrate: тот не добавил бы
+// обязательный столбец в заполненную таблицу и не изменил бы существующий уникальный индекс.
+func AddAttendanceOccurrence(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&gorm_models.Attendance{}) || db.Migrator().HasColumn(&gorm_models.Attendance{}, "OccurrenceStart") {
+		return nil
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, query := range []string{
+			"ALTER TABLE attendances ADD COLUMN occurrence_start timestamp with time zone",
+			"UPDATE attendances SET occurrence_start = events.datetime_start FROM events WHERE events.id_event = attendances.id_event",
+			"DELETE FROM attendances WHERE occurrence_start IS NULL",
+			"ALTER TABLE attendances ALTER COLUMN occurrence_start SET NOT NULL",
+		} {
+			if err := tx.Exec(query).Error; err != nil {
+				return err
+			}
+		}
+		if tx.Migrator().HasIndex(&gorm_models.Attendance{}, "idx_attendance") {
+			if err := tx.Migrator().DropIndex(&gorm_models.Attendance{}, "idx_attendance"); err != nil {
+				return err
+			}
+		}
+		return tx.Migrator().CreateIndex(&gorm_models.Attendance{}, "idx_attendance")
+	})
+}
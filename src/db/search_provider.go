@@ -0,0 +1,81 @@
+package db
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"aliorToDoBot/src/db/gorm_models"
+)
+
+type providerSearch interface {
+	SearchEvents(Filter EventFilter, Limit int, Offset int) ([]gorm_models.Event, int64, error)
+}
+
+// EventFilter описывает условия поиска мероприятий. Пустые поля не ограничивают поиск.
+type EventFilter struct {
+	Query    string     // подстрока названия, без учёта регистра
+	Category string     // название категории
+	IDGroup  int64      // ID группы
+	Status   string     // статус мероприятия
+	From     *time.Time // начало периода
+	To       *time.Time // конец периода, не включительно
+}
+
+// IsEmpty сообщает, что ни одно условие фильтра не задано.
+func (f EventFilter) IsEmpty() bool {
+	return f.Query == "" && f.Category == "" && f.IDGroup == 0 && f.Status == "" && f.From == nil && f.To == nil
+}
+
+// likeEscaper экранирует спецсимволы шаблона LIKE во введённой строке
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// SearchEvents ищет мероприятия в группах пользователя по фильтру и возвращает страницу результатов
+// и общее число найденных мероприятий. Повторяющееся мероприятие попадает в период,
+// если серия началась до его конца и не закончилась до его начала.
+func (g *GormProvider) SearchEvents(ctx context.Context, chatID int64, filter EventFilter, limit, offset int) ([]gorm_models.Event, int64, error) {
+	user, err := g.userByChatID(ctx, chatID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := g.WithContext(ctx).Model(&gorm_models.Event{}).
+		Where("id_group IN (?)", g.WithContext(ctx).Model(&gorm_models.Membership{}).
+			Select("id_group").
+			Where("id_user = ?", user.IDUser))
+
+	if filter.Query != "" {
+		query = query.Where("name_event ILIKE ?", "%"+likeEscaper.Replace(filter.Query)+"%")
+	}
+	if filter.Category != "" {
+		query = query.Where("category = ?", filter.Category)
+	}
+	if filter.IDGroup != 0 {
+		query = query.Where("id_group = ?", filter.IDGroup)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.From != nil {
+		query = query.Where("datetime_start >= ? OR (recur_freq <> '' AND (recur_until IS NULL OR recur_until >= ?))",
+			*filter.From, *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("datetime_start < ?", *filter.To)
+	}
+
+	var total int64
+	if err = query.Count(&total).Error; err != nil {
+		return nil, 0, errInternal
+	}
+
+	var events []gorm_models.Event
+	if err = query.Preload("Checklist").
+		Order("datetime_start, id_event").
+		Limit(limit).
+		Offset(offset).
+		Find(&events).Error; err != nil {
+		return nil, 0, errInternal
+	}
+	return events, total, nil
+}
@@ -0,0 +1,271 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+
+	"aliorToDoBot/src/db/gorm_models"
+)
+
+const maxTaskColumnNameLength = 20
+
+type providerTaskColumn interface {
+	GetTaskColumn(IDColumn int64) (gorm_models.TaskColumn, error)
+	GetTaskColumns(IDGroup int64) ([]gorm_models.TaskColumn, error)
+	CreateDefaultTaskColumns(IDGroup int64) error
+	CreateTaskColumn(IDGroup int64, Name string) error
+	RenameTaskColumn(IDColumn int64, Name string) error
+	DeleteTaskColumn(IDColumn int64) error
+	GetGroupTasks(IDGroup int64) ([]gorm_models.Task, error)
+	MoveTask(IDTask int64, IDColumn int64) error
+}
+
+// GetTaskColumn возвращает колонку доски по её ID.
+func (g *GormProvider) GetTaskColumn(ctx context.Context, idColumn int64) (gorm_models.TaskColumn, error) {
+	var column gorm_models.TaskColumn
+	if err := g.WithContext(ctx).First(&column, idColumn).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return column, errNoTaskColumn
+		}
+		return column, errInternal
+	}
+	return column, nil
+}
+
+// GetTaskColumns возвращает колонки доски группы по порядку.
+// Колонки выполненных задач всегда идут последними.
+// Группам, созданным до появления доски, стандартные колонки добавляются при первом обращении.
+func (g *GormProvider) GetTaskColumns(ctx context.Context, idGroup int64) ([]gorm_models.TaskColumn, error) {
+	var columns []gorm_models.TaskColumn
+	if err := g.WithContext(ctx).Where("id_group = ?", idGroup).
+		Order("is_done, position, id_column").
+		Find(&columns).Error; err != nil {
+		return nil, errInternal
+	}
+	if len(columns) > 0 {
+		return columns, nil
+	}
+
+	if err := g.CreateDefaultTaskColumns(ctx, idGroup); err != nil {
+		return nil, err
+	}
+	if err := g.WithContext(ctx).Where("id_group = ?", idGroup).
+		Order("is_done, position, id_column").
+		Find(&columns).Error; err != nil {
+		return nil, errInternal
+	}
+	return columns, nil
+}
+
+// CreateDefaultTaskColumns добавляет доске новой группы стандартные колонки.
+func (g *GormProvider) CreateDefaultTaskColumns(ctx context.Context, idGroup int64) error {
+	columns := make([]gorm_models.TaskColumn, 0, len(gorm_models.DefaultTaskColumns))
+	for _, column := range gorm_models.DefaultTaskColumns {
+		column.IDGroup = idGroup
+		columns = append(columns, column)
+	}
+	if err := g.WithContext(ctx).Create(&columns).Error; err != nil {
+		return errInternal
+	}
+	return nil
+}
+
+// CreateTaskColumn добавляет колонку на доску группы перед колонками выполненных задач.
+// Изменять колонки может только администратор группы.
+func (g *GormProvider) CreateTaskColumn(ctx context.Context, chatID int64, idGroup int64, name string) error {
+	name = strings.TrimSpace(name)
+	if err := validateTaskColumnName(name); err != nil {
+		return err
+	}
+
+	isAdmin, err := g.isAdmin(ctx, chatID, idGroup)
+	if err != nil {
+		return err
+	}
+	if !isAdmin {
+		return fmt.Errorf("только администратор может изменять колонки доски")
+	}
+
+	columns, err := g.GetTaskColumns(ctx, idGroup)
+	if err != nil {
+		return err
+	}
+	position := 0
+	for _, column := range columns {
+		if strings.EqualFold(column.Name, name) {
+			return fmt.Errorf("колонка «%s» уже есть на доске", name)
+		}
+		if !column.IsDone && column.Position > position {
+			position = column.Position
+		}
+	}
+
+	if err = g.WithContext(ctx).Create(&gorm_models.TaskColumn{
+		IDGroup:  idGroup,
+		Name:     name,
+		Position: position + 1,
+	}).Error; err != nil {
+		return errInternal
+	}
+	return nil
+}
+
+// RenameTaskColumn переименовывает колонку доски.
+func (g *GormProvider) RenameTaskColumn(ctx context.Context, chatID int64, idColumn int64, name string) error {
+	name = strings.TrimSpace(name)
+	if err := validateTaskColumnName(name); err != nil {
+		return err
+	}
+
+	column, err := g.taskColumnForAdmin(ctx, chatID, idColumn)
+	if err != nil {
+		return err
+	}
+
+	var count int64
+	if err = g.WithContext(ctx).Model(&gorm_models.TaskColumn{}).
+		Where("id_group = ? AND LOWER(name) = LOWER(?) AND id_column <> ?", column.IDGroup, name, idColumn).
+		Count(&count).Error; err != nil {
+		return errInternal
+	}
+	if count > 0 {
+		return fmt.Errorf("колонка «%s» уже есть на доске", name)
+	}
+
+	if err = g.WithContext(ctx).Model(&column).Update("name", name).Error; err != nil {
+		return errInternal
+	}
+	return nil
+}
+
+// DeleteTaskColumn удаляет колонку доски.
+// Нельзя удалить колонку с задачами, а также последнюю колонку невыполненных или выполненных задач.
+func (g *GormProvider) DeleteTaskColumn(ctx context.Context, chatID int64, idColumn int64) error {
+	column, err := g.taskColumnForAdmin(ctx, chatID, idColumn)
+	if err != nil {
+		return err
+	}
+
+	var count int64
+	if err = g.WithContext(ctx).Model(&gorm_models.Task{}).Where("id_column = ?", idColumn).Count(&count).Error; err != nil {
+		return errInternal
+	}
+	if count > 0 {
+		return fmt.Errorf("в колонке «%s» есть задачи (%d), сначала перенесите их", column.Name, count)
+	}
+
+	if err = g.WithContext(ctx).Model(&gorm_models.TaskColumn{}).
+		Where("id_group = ? AND is_done = ? AND id_column <> ?", column.IDGroup, column.IsDone, idColumn).
+		Count(&count).Error; err != nil {
+		return errInternal
+	}
+	if count == 0 {
+		if column.IsDone {
+			return fmt.Errorf("на доске должна остаться колонка для выполненных задач")
+		}
+		return fmt.Errorf("на доске должна остаться колонка для невыполненных задач")
+	}
+
+	if err = g.WithContext(ctx).Delete(&column).Error; err != nil {
+		return errInternal
+	}
+	return nil
+}
+
+// GetGroupTasks возвращает все задачи группы вместе с исполнителями.
+// Задачи видят только участники группы.
+func (g *GormProvider) GetGroupTasks(ctx context.Context, chatID int64, idGroup int64) ([]gorm_models.Task, error) {
+	if _, err := g.groupMember(ctx, chatID, idGroup); err != nil {
+		return nil, err
+	}
+
+	var tasks []gorm_models.Task
+	if err := g.WithContext(ctx).Preload("Assignees.User").
+		Where("id_group = ?", idGroup).
+		Order(taskOrder).
+		Find(&tasks).Error; err != nil {
+		return nil, errInternal
+	}
+	return tasks, nil
+}
+
+// MoveTask переносит задачу в другую колонку доски её группы.
+// Перенос в колонку выполненных задач отмечает задачу выполненной, а обратно — снимает отметку.
+func (g *GormProvider) MoveTask(ctx context.Context, chatID int64, idTask int64, idColumn int64) error {
+	task, err := g.GetTask(ctx, idTask)
+	if err != nil {
+		return err
+	}
+	if _, err = g.groupMember(ctx, chatID, task.IDGroup); err != nil {
+		return err
+	}
+
+	column, err := g.GetTaskColumn(ctx, idColumn)
+	if err != nil {
+		return err
+	}
+	if column.IDGroup != task.IDGroup {
+		return fmt.Errorf("колонка не относится к группе задачи")
+	}
+
+	updates := map[string]interface{}{"id_column": idColumn, "is_done": column.IsDone}
+	switch {
+	case column.IsDone && !task.IsDone:
+		updates["done_at"] = time.Now()
+	case !column.IsDone:
+		updates["done_at"] = nil
+	}
+	if err = g.WithContext(ctx).Model(&task).Updates(updates).Error; err != nil {
+		return errInternal
+	}
+	return nil
+}
+
+// firstTaskColumn возвращает первую колонку доски группы с указанным признаком выполнения.
+// Если такой колонки нет, возвращается nil.
+func (g *GormProvider) firstTaskColumn(ctx context.Context, idGroup int64, isDone bool) (*int64, error) {
+	columns, err := g.GetTaskColumns(ctx, idGroup)
+	if err != nil {
+		return nil, err
+	}
+	for _, column := range columns {
+		if column.IsDone == isDone {
+			return &column.IDColumn, nil
+		}
+	}
+	return nil, nil
+}
+
+// taskColumnForAdmin возвращает колонку, если пользователь администрирует её группу.
+func (g *GormProvider) taskColumnForAdmin(ctx context.Context, chatID int64, idColumn int64) (gorm_models.TaskColumn, error) {
+	column, err := g.GetTaskColumn(ctx, idColumn)
+	if err != nil {
+		return column, err
+	}
+
+	isAdmin, err := g.isAdmin(ctx, chatID, column.IDGroup)
+	if err != nil {
+		return column, err
+	}
+	if !isAdmin {
+		return column, fmt.Errorf("только администратор может изменять колонки доски")
+	}
+	return column, nil
+}
+
+// validateTaskColumnName проверяет название колонки доски.
+func validateTaskColumnName(name string) error {
+	if name == "" {
+		return fmt.Errorf("название колонки не может быть пустым")
+	}
+	if utf8.RuneCountInString(name) > maxTaskColumnNameLength {
+		return fmt.Errorf("название колонки должно быть не длиннее %d символов", maxTaskColumnNameLength)
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"aliorToDoBot/src/db/gorm_models"
+)
+
+type providerTask interface {
+	GetTask(IDTask int64) (gorm_models.Task, error)
+	GetUserTasks(ChatID int64, WithDone bool) ([]gorm_models.Task, error)
+	CreateTask(ChatID int64, IDGroup int64, Title string, Priority string, Deadline *time.Time) error
+	CompleteTask(ChatID int64, IDTask int64) error
+	DeleteTask(ChatID int64, IDTask int64) error
+	GetAssignedTasks(ChatID int64) ([]gorm_models.Task, error)
+	ToggleTaskAssignee(ChatID int64, IDTask int64, IDUser int64) (bool, error)
+}
+
+// taskOrder сортирует задачи: сначала с ближайшим сроком, при равных сроках — более важные.
+const taskOrder = "is_done, deadline IS NULL, deadline, CASE priority WHEN 'high' THEN 0 WHEN 'normal' THEN 1 ELSE 2 END, id_task"
+
+// GetTask возвращает задачу по её ID вместе с исполнителями.
+func (g *GormProvider) GetTask(ctx context.Context, idTask int64) (gorm_models.Task, error) {
+	var task gorm_models.Task
+	if err := g.WithContext(ctx).Preload("Assignees.User").First(&task, idTask).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return task, errNoTask
+		}
+		return task, errInternal
+	}
+	return task, nil
+}
+
+// GetUserTasks возвращает задачи всех групп пользователя вместе с исполнителями.
+// Выполненные задачи возвращаются только при withDone.
+func (g *GormProvider) GetUserTasks(ctx context.Context, chatID int64, withDone bool) ([]gorm_models.Task, error) {
+	user, err := g.userByChatID(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := g.WithContext(ctx).Where("id_group IN (?)", g.WithContext(ctx).Model(&gorm_models.Membership{}).
+		Select("id_group").
+		Where("id_user = ?", user.IDUser))
+	if !withDone {
+		query = query.Where("is_done = false")
+	}
+
+	var tasks []gorm_models.Task
+	if err = query.Preload("Assignees.User").Order(taskOrder).Find(&tasks).Error; err != nil {
+		return nil, errInternal
+	}
+	return tasks, nil
+}
+
+// CreateTask добавляет задачу в группу.
+// Создавать задачи может любой участник группы.
+func (g *GormProvider) CreateTask(ctx context.Context, chatID int64, idGroup int64, title, priority string,
+	deadline *time.Time) error {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return fmt.Errorf("название задачи не может быть пустым")
+	}
+	switch priority {
+	case gorm_models.TaskPriorityHigh, gorm_models.TaskPriorityNormal, gorm_models.TaskPriorityLow:
+	default:
+		return fmt.Errorf("неизвестный приоритет: %s", priority)
+	}
+
+	user, err := g.groupMember(ctx, chatID, idGroup)
+	if err != nil {
+		return err
+	}
+
+	// Новая задача попадает в первую колонку доски
+	idColumn, err := g.firstTaskColumn(ctx, idGroup, false)
+	if err != nil {
+		return err
+	}
+
+	if err = g.WithContext(ctx).Create(&gorm_models.Task{
+		IDGroup:   idGroup,
+		Title:     title,
+		Priority:  priority,
+		Deadline:  deadline,
+		IDColumn:  idColumn,
+		IDCreator: user.IDUser,
+	}).Error; err != nil {
+		return errInternal
+	}
+	return nil
+}
+
+// CompleteTask отмечает задачу выполненной и переносит её в колонку выполненных задач.
+// Отметить задачу может любой участник её группы.
+func (g *GormProvider) CompleteTask(ctx context.Context, chatID int64, idTask int64) error {
+	task, err := g.GetTask(ctx, idTask)
+	if err != nil {
+		return err
+	}
+	if _, err = g.groupMember(ctx, chatID, task.IDGroup); err != nil {
+		return err
+	}
+	if task.IsDone {
+		return fmt.Errorf("задача уже выполнена")
+	}
+
+	idColumn, err := g.firstTaskColumn(ctx, task.IDGroup, true)
+	if err != nil {
+		return err
+	}
+
+	if err = g.WithContext(ctx).Model(&task).Updates(map[string]interface{}{
+		"is_done":   true,
+		"done_at":   time.Now(),
+		"id_column": idColumn,
+	}).Error; err != nil {
+		return errInternal
+	}
+	return nil
+}
+
+// DeleteTask удаляет задачу.
+// Удалить задачу может её автор или администратор группы.
+func (g *GormProvider) DeleteTask(ctx context.Context, chatID int64, idTask int64) error {
+	task, err := g.GetTask(ctx, idTask)
+	if err != nil {
+		return err
+	}
+	user, err := g.userByChatID(ctx, chatID)
+	if err != nil {
+		return err
+	}
+
+	if task.IDCreator != user.IDUser {
+		isAdmin, err := g.isAdmin(ctx, chatID, task.IDGroup)
+		if err != nil {
+			return err
+		}
+		if !isAdmin {
+			return fmt.Errorf("удалить задачу может только её автор или администратор группы")
+		}
+	}
+
+	if err = g.WithContext(ctx).Delete(&task).Error; err != nil {
+		return errInternal
+	}
+	return nil
+}
+
+// GetAssignedTasks возвращает невыполненные задачи, назначенные пользователю, во всех его группах.
+func (g *GormProvider) GetAssignedTasks(ctx context.Context, chatID int64) ([]gorm_models.Task, error) {
+	user, err := g.userByChatID(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []gorm_models.Task
+	if err = g.WithContext(ctx).Preload("Assignees.User").
+		Where("is_done = false AND id_task IN (?)", g.WithContext(ctx).Model(&gorm_models.TaskAssignee{}).
+			Select("id_task").
+			Where("id_user = ?", user.IDUser)).
+		Order(taskOrder).
+		Find(&tasks).Error; err != nil {
+		return nil, errInternal
+	}
+	return tasks, nil
+}
+
+// ToggleTaskAssignee назначает задачу участнику группы или снимает назначение.
+// Назначать исполнителей может только администратор группы.
+// Возвращает true, если участник стал исполнителем.
+func (g *GormProvider) ToggleTaskAssignee(ctx context.Context, chatID int64, idTask int64, idUser int64) (bool, error) {
+	task, err := g.GetTask(ctx, idTask)
+	if err != nil {
+		return false, err
+	}
+
+	isAdmin, err := g.isAdmin(ctx, chatID, task.IDGroup)
+	if err != nil {
+		return false, err
+	}
+	if !isAdmin {
+		return false, fmt.Errorf("назначать исполнителей может только администратор группы")
+	}
+
+	var count int64
+	if err = g.WithContext(ctx).Model(&gorm_models.Membership{}).
+		Where("id_group = ? AND id_user = ?", task.IDGroup, idUser).
+		Count(&count).Error; err != nil {
+		return false, errInternal
+	}
+	if count == 0 {
+		return false, fmt.Errorf("пользователь не состоит в группе задачи")
+	}
+
+	for _, assignee := range task.Assignees {
+		if assignee.IDUser == idUser {
+			if err = g.WithContext(ctx).
+				Where("id_task = ? AND id_user = ?", idTask, idUser).
+				Delete(&gorm_models.TaskAssignee{}).Error; err != nil {
+				return false, errInternal
+			}
+			return false, nil
+		}
+	}
+
+	if err = g.WithContext(ctx).Omit("User").Create(&gorm_models.TaskAssignee{IDTask: idTask, IDUser: idUser}).Error; err != nil {
+		return false, errInternal
+	}
+	return true, nil
+}
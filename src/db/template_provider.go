@@ -0,0 +1,148 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+
+	"aliorToDoBot/src/db/gorm_models"
+)
+
+const maxTemplateNameLength = 50
+
+type providerTemplate interface {
+	GetUserTemplates(ChatID int64) ([]gorm_models.EventTemplate, error)
+	GetTemplate(ChatID int64, IDTemplate int64) (gorm_models.EventTemplate, error)
+	CreateTemplateFromEvent(ChatID int64, IDEvent int64, Name string, Shared bool) error
+	DeleteTemplate(ChatID int64, IDTemplate int64) error
+}
+
+// GetUserTemplates возвращает личные шаблоны пользователя и общие шаблоны его групп.
+func (g *GormProvider) GetUserTemplates(ctx context.Context, chatID int64) ([]gorm_models.EventTemplate, error) {
+	user, err := g.userByChatID(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	var templates []gorm_models.EventTemplate
+	if err = g.WithContext(ctx).
+		Where("id_user = ?", user.IDUser).
+		Or("id_user IS NULL AND id_group IN (?)", g.WithContext(ctx).Model(&gorm_models.Membership{}).
+			Select("id_group").
+			Where("id_user = ?", user.IDUser)).
+		Order("name, id_template").
+		Find(&templates).Error; err != nil {
+		return nil, errInternal
+	}
+	return templates, nil
+}
+
+// GetTemplate возвращает шаблон, если он доступен пользователю.
+func (g *GormProvider) GetTemplate(ctx context.Context, chatID int64, idTemplate int64) (gorm_models.EventTemplate, error) {
+	var template gorm_models.EventTemplate
+	if err := g.WithContext(ctx).First(&template, idTemplate).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return template, errNoTemplate
+		}
+		return template, errInternal
+	}
+
+	user, err := g.groupMember(ctx, chatID, template.IDGroup)
+	if err != nil {
+		return template, errNoTemplate
+	}
+	if template.IDUser != nil && *template.IDUser != user.IDUser {
+		return template, errNoTemplate
+	}
+	return template, nil
+}
+
+// CreateTemplateFromEvent сохраняет мероприятие как шаблон.
+// Личный шаблон может сохранить любой участник группы, общий — только администратор.
+func (g *GormProvider) CreateTemplateFromEvent(ctx context.Context, chatID int64, idEvent int64, name string, shared bool) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("название шаблона не может быть пустым")
+	}
+	if utf8.RuneCountInString(name) > maxTemplateNameLength {
+		return fmt.Errorf("название шаблона должно быть не длиннее %d символов", maxTemplateNameLength)
+	}
+
+	event, err := g.eventByID(ctx, idEvent)
+	if err != nil {
+		return err
+	}
+	user, err := g.groupMember(ctx, chatID, event.IDGroup)
+	if err != nil {
+		return err
+	}
+
+	template := gorm_models.EventTemplate{
+		Name:          name,
+		IDGroup:       event.IDGroup,
+		IDCreator:     user.IDUser,
+		NameEvent:     event.NameEvent,
+		Category:      event.Category,
+		Duration:      event.Duration,
+		IsAllDay:      event.IsAllDay,
+		LinkToVideo:   event.LinkToVideo,
+		EventLocation: event.EventLocation,
+	}
+
+	// Мероприятия по шаблону создаются в его группе, а это доступно только администраторам
+	isAdmin, err := g.isAdmin(ctx, chatID, event.IDGroup)
+	if err != nil {
+		return err
+	}
+	if !isAdmin {
+		return fmt.Errorf("шаблоны мероприятий группы может сохранять только администратор")
+	}
+
+	scope := g.WithContext(ctx).Model(&gorm_models.EventTemplate{}).Where("name = ?", name)
+	if shared {
+		scope = scope.Where("id_user IS NULL AND id_group = ?", event.IDGroup)
+	} else {
+		template.IDUser = &user.IDUser
+		scope = scope.Where("id_user = ?", user.IDUser)
+	}
+
+	var count int64
+	if err = scope.Count(&count).Error; err != nil {
+		return errInternal
+	}
+	if count > 0 {
+		return fmt.Errorf("шаблон «%s» уже существует", name)
+	}
+
+	if err = g.WithContext(ctx).Create(&template).Error; err != nil {
+		return errInternal
+	}
+	return nil
+}
+
+// DeleteTemplate удаляет шаблон. Личный шаблон удаляет владелец, общий — администратор группы.
+func (g *GormProvider) DeleteTemplate(ctx context.Context, chatID int64, idTemplate int64) error {
+	template, err := g.GetTemplate(ctx, chatID, idTemplate)
+	if err != nil {
+		return err
+	}
+
+	if template.IDUser == nil {
+		isAdmin, err := g.isAdmin(ctx, chatID, template.IDGroup)
+		if err != nil {
+			return err
+		}
+		if !isAdmin {
+			return fmt.Errorf("общий шаблон может удалить только администратор группы")
+		}
+	}
+
+	if err = g.WithContext(ctx).Delete(&template).Error; err != nil {
+		return errInternal
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"aliorToDoBot/src/db/gorm_models"
+)
+
+type providerTrash interface {
+	TrashEvent(ChatID int64, IDEvent int64) error
+	GetTrash(ChatID int64) ([]gorm_models.Event, error)
+	RestoreEvent(ChatID int64, IDEvent int64, DeletedSince time.Time) error
+	PurgeEvent(ChatID int64, IDEvent int64) error
+	PurgeTrash(Before time.Time) (int64, error)
+}
+
+// trashedEvent возвращает удалённое мероприятие, если пользователь администратор его группы.
+func (g *GormProvider) trashedEvent(ctx context.Context, chatID int64, idEvent int64) (gorm_models.Event, error) {
+	var event gorm_models.Event
+	if err := g.WithContext(ctx).Unscoped().
+		Where("deleted_at IS NOT NULL").
+		First(&event, idEvent).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return event, fmt.Errorf("мероприятия нет в корзине")
+		}
+		return event, errInternal
+	}
+
+	isAdmin, err := g.isAdmin(ctx, chatID, event.IDGroup)
+	if err != nil {
+		return event, err
+	}
+	if !isAdmin {
+		return event, fmt.Errorf("только администратор может управлять корзиной группы")
+	}
+	return event, nil
+}
+
+// TrashEvent переносит мероприятие в корзину. Удалить мероприятие может только администратор группы.
+func (g *GormProvider) TrashEvent(ctx context.Context, chatID int64, idEvent int64) error {
+	event, err := g.eventByID(ctx, idEvent)
+	if err != nil {
+		return err
+	}
+
+	isAdmin, err := g.isAdmin(ctx, chatID, event.IDGroup)
+	if err != nil {
+		return err
+	}
+	if !isAdmin {
+		return fmt.Errorf("только администратор может удалить событие")
+	}
+
+	if err = g.WithContext(ctx).Delete(&event).Error; err != nil {
+		return errInternal
+	}
+	return nil
+}
+
+// GetTrash возвращает удалённые мероприятия групп, в которых пользователь администратор.
+// Сначала идут удалённые последними.
+func (g *GormProvider) GetTrash(ctx context.Context, chatID int64) ([]gorm_models.Event, error) {
+	user, err := g.userByChatID(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []gorm_models.Event
+	if err = g.WithContext(ctx).Unscoped().
+		Where("deleted_at IS NOT NULL AND id_group IN (?)", g.WithContext(ctx).Model(&gorm_models.Membership{}).
+			Select("id_group").
+			Where("id_user = ? AND is_admin = true", user.IDUser)).
+		Order("deleted_at DESC, id_event").
+		Find(&events).Error; err != nil {
+		return nil, errInternal
+	}
+	return events, nil
+}
+
+// RestoreEvent возвращает мероприятие из корзины.
+// Мероприятие, удалённое раньше deletedSince, не восстанавливается: так ограничивается время отмены удаления.
+func (g *GormProvider) RestoreEvent(ctx context.Context, chatID int64, idEvent int64, deletedSince time.Time) error {
+	event, err := g.trashedEvent(ctx, chatID, idEvent)
+	if err != nil {
+		return err
+	}
+	if event.DeletedAt.Time.Before(deletedSince) {
+		return fmt.Errorf("время отмены удаления истекло")
+	}
+
+	if err = g.WithContext(ctx).Unscoped().Model(&event).Update("deleted_at", nil).Error; err != nil {
+		return errInternal
+	}
+	return nil
+}
+
+// PurgeEvent окончательно удаляет мероприятие из корзины вместе со связанными данными.
+func (g *GormProvider) PurgeEvent(ctx context.Context, chatID int64, idEvent int64) error {
+	event, err := g.trashedEvent(ctx, chatID, idEvent)
+	if err != nil {
+		return err
+	}
+
+	if err = g.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return purgeEvents(tx, []int64{event.IDEvent})
+	}); err != nil {
+		return errInternal
+	}
+	return nil
+}
+
+// PurgeTrash окончательно удаляет мероприятия, попавшие в корзину раньше before, вместе со связанными данными.
+// Возвращает количество удалённых мероприятий.
+func (g *GormProvider) PurgeTrash(ctx context.Context, before time.Time) (int64, error) {
+	var ids []int64
+	if err := g.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&gorm_models.Event{}).
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+			Pluck("id_event", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		return purgeEvents(tx, ids)
+	}); err != nil {
+		return 0, errInternal
+	}
+	return int64(len(ids)), nil
+}
+
+// eventDependents данные мероприятия, которые база не удаляет каскадно вместе с ним
+var eventDependents = []interface{}{
+	&gorm_models.Attendance{},
+	&gorm_models.EventException{},
+	&gorm_models.SentReminder{},
+	&gorm_models.ReminderPreference{},
+}
+
+// purgeEvents удаляет мероприятия и их данные. Вызывается внутри транзакции.
+func purgeEvents(tx *gorm.DB, ids []int64) error {
+	for _, model := range eventDependents {
+		if err := tx.Where("id_event IN ?", ids).Delete(model).Error; err != nil {
+			return err
+		}
+	}
+	return tx.Unscoped().Where("id_event IN ?", ids).Delete(&gorm_models.Event{}).Error
+}
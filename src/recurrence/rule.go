@@ -0,0 +1,303 @@
+package recurrence
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Частоты повторения, поддерживаемые правилом
+const (
+	Daily   = "daily"
+	Weekly  = "weekly"
+	Monthly = "monthly"
+)
+
+// maxIterations ограничивает перебор повторений, чтобы некорректное правило не зациклило бота
+const maxIterations = 10000
+
+// Rule описывает правило повторения мероприятия (упрощённый аналог RRULE)
+type Rule struct {
+	Freq     string
+	Interval int
+	Weekdays []time.Weekday
+	Until    *time.Time
+	Count    int
+}
+
+var weekdayCodes = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+var weekdayNames = map[time.Weekday]string{
+	time.Monday:    "пн",
+	time.Tuesday:   "вт",
+	time.Wednesday: "ср",
+	time.Thursday:  "чт",
+	time.Friday:    "пт",
+	time.Saturday:  "сб",
+	time.Sunday:    "вс",
+}
+
+// IsZero сообщает, что правило не задано и мероприятие не повторяется
+func (r Rule) IsZero() bool {
+	return r.Freq == ""
+}
+
+// Parse разбирает правило в формате RRULE, например FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=10.
+// Дата в UNTIL указывается в формате ГГГГММДД.
+func Parse(input string) (Rule, error) {
+	rule := Rule{Interval: 1}
+	input = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(input)), "RRULE:")
+
+	for _, part := range strings.Split(input, ";") {
+		if part == "" {
+			continue
+		}
+		key, value, found := strings.Cut(part, "=")
+		if !found {
+			return Rule{}, fmt.Errorf("некорректная часть правила: %s", part)
+		}
+
+		switch key {
+		case "FREQ":
+			switch value {
+			case "DAILY":
+				rule.Freq = Daily
+			case "WEEKLY":
+				rule.Freq = Weekly
+			case "MONTHLY":
+				rule.Freq = Monthly
+			default:
+				return Rule{}, fmt.Errorf("неподдерживаемая частота: %s", value)
+			}
+		case "INTERVAL":
+			interval, err := strconv.Atoi(value)
+			if err != nil || interval < 1 {
+				return Rule{}, fmt.Errorf("некорректный интервал: %s", value)
+			}
+			rule.Interval = interval
+		case "BYDAY":
+			weekdays, err := ParseWeekdays(value)
+			if err != nil {
+				return Rule{}, err
+			}
+			rule.Weekdays = weekdays
+		case "UNTIL":
+			until, err := time.Parse("20060102", value)
+			if err != nil {
+				return Rule{}, fmt.Errorf("некорректная дата окончания: %s", value)
+			}
+			// Дата окончания включительна
+			until = until.Add(24*time.Hour - time.Second)
+			rule.Until = &until
+		case "COUNT":
+			count, err := strconv.Atoi(value)
+			if err != nil || count < 1 {
+				return Rule{}, fmt.Errorf("некорректное количество повторений: %s", value)
+			}
+			rule.Count = count
+		default:
+			return Rule{}, fmt.Errorf("неизвестный параметр правила: %s", key)
+		}
+	}
+
+	if rule.Freq == "" {
+		return Rule{}, fmt.Errorf("не указана частота FREQ")
+	}
+	if len(rule.Weekdays) > 0 && rule.Freq != Weekly {
+		return Rule{}, fmt.Errorf("BYDAY поддерживается только для FREQ=WEEKLY")
+	}
+	return rule, nil
+}
+
+// ParseWeekdays разбирает список дней недели вида MO,WE,FR
+func ParseWeekdays(input string) ([]time.Weekday, error) {
+	var weekdays []time.Weekday
+	for _, code := range strings.Split(strings.ToUpper(input), ",") {
+		code = strings.TrimSpace(code)
+		if code == "" {
+			continue
+		}
+		weekday, ok := weekdayCodes[code]
+		if !ok {
+			return nil, fmt.Errorf("некорректный день недели: %s", code)
+		}
+		weekdays = append(weekdays, weekday)
+	}
+	return weekdays, nil
+}
+
+// FormatWeekdays возвращает дни недели в виде MO,WE,FR
+func FormatWeekdays(weekdays []time.Weekday) string {
+	codes := make([]string, 0, len(weekdays))
+	for _, weekday := range weekdays {
+		for code, w := range weekdayCodes {
+			if w == weekday {
+				codes = append(codes, code)
+				break
+			}
+		}
+	}
+	return strings.Join(codes, ",")
+}
+
+// Describe возвращает описание правила для пользователя
+func (r Rule) Describe() string {
+	if r.IsZero() {
+		return "не повторяется"
+	}
+
+	var result string
+	switch r.Freq {
+	case Daily:
+		result = "каждый день"
+		if r.Interval > 1 {
+			result = fmt.Sprintf("каждые %d дн.", r.Interval)
+		}
+	case Weekly:
+		result = "каждую неделю"
+		if r.Interval > 1 {
+			result = fmt.Sprintf("каждые %d нед.", r.Interval)
+		}
+		if len(r.Weekdays) > 0 {
+			names := make([]string, 0, len(r.Weekdays))
+			for _, weekday := range r.Weekdays {
+				names = append(names, weekdayNames[weekday])
+			}
+			result += " (" + strings.Join(names, ", ") + ")"
+		}
+	case Monthly:
+		result = "каждый месяц"
+		if r.Interval > 1 {
+			result = fmt.Sprintf("каждые %d мес.", r.Interval)
+		}
+	}
+
+	if r.Until != nil {
+		result += ", до " + r.Until.Format("02.01.2006")
+	}
+	if r.Count > 0 {
+		result += fmt.Sprintf(", %d раз", r.Count)
+	}
+	return result
+}
+
+// Between возвращает начала повторений, попадающие в интервал [from, to).
+// Повторения считаются от start, но сам start входит в них, только если подходит под правило:
+// например, при BYDAY=MO,WE начало во вторник пропускается.
+func (r Rule) Between(start, from, to time.Time) []time.Time {
+	var result []time.Time
+	r.iterate(start, func(t time.Time) bool {
+		if !t.Before(to) {
+			return false
+		}
+		if !t.Before(from) {
+			result = append(result, t)
+		}
+		return true
+	})
+	return result
+}
+
+// Next возвращает первое повторение строго после after.
+// Второе значение равно false, если повторений больше нет.
+func (r Rule) Next(start, after time.Time) (time.Time, bool) {
+	var (
+		next  time.Time
+		found bool
+	)
+	r.iterate(start, func(t time.Time) bool {
+		if t.After(after) {
+			next, found = t, true
+			return false
+		}
+		return true
+	})
+	return next, found
+}
+
+// iterate перебирает повторения по порядку, пока yield возвращает true
+func (r Rule) iterate(start time.Time, yield func(time.Time) bool) {
+	if r.IsZero() {
+		yield(start)
+		return
+	}
+
+	interval := r.Interval
+	if interval < 1 {
+		interval = 1
+	}
+
+	emitted := 0
+	emit := func(t time.Time) bool {
+		if t.Before(start) {
+			return true
+		}
+		if r.Until != nil && t.After(*r.Until) {
+			return false
+		}
+		if r.Count > 0 && emitted >= r.Count {
+			return false
+		}
+		emitted++
+		return yield(t)
+	}
+
+	year, month, day := start.Date()
+	hour, minute, sec := start.Clock()
+	loc := start.Location()
+
+	for i := 0; i < maxIterations; i++ {
+		switch r.Freq {
+		case Daily:
+			if !emit(time.Date(year, month, day+i*interval, hour, minute, sec, 0, loc)) {
+				return
+			}
+		case Weekly:
+			weekdays := r.Weekdays
+			if len(weekdays) == 0 {
+				weekdays = []time.Weekday{start.Weekday()}
+			}
+			// Неделя начинается с понедельника
+			offset := (int(start.Weekday()) + 6) % 7
+			weekStart := day - offset + i*7*interval
+			for d := 0; d < 7; d++ {
+				candidate := time.Date(year, month, weekStart+d, hour, minute, sec, 0, loc)
+				if !containsWeekday(weekdays, candidate.Weekday()) {
+					continue
+				}
+				if !emit(candidate) {
+					return
+				}
+			}
+		case Monthly:
+			candidate := time.Date(year, month+time.Month(i*interval), day, hour, minute, sec, 0, loc)
+			// Пропускаем месяцы, в которых нет нужного числа (например, 31-го)
+			if candidate.Day() != day {
+				continue
+			}
+			if !emit(candidate) {
+				return
+			}
+		default:
+			return
+		}
+	}
+}
+
+func containsWeekday(weekdays []time.Weekday, weekday time.Weekday) bool {
+	for _, w := range weekdays {
+		if w == weekday {
+			return true
+		}
+	}
+	return false
+}
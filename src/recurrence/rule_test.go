@@ -0,0 +1,195 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+)
+
+var msk = time.FixedZone("MSK", 3*60*60)
+
+func date(year int, month time.Month, day, hour int) time.Time {
+	return time.Date(year, month, day, hour, 0, 0, 0, msk)
+}
+
+func TestRuleBetween(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  string
+		start time.Time
+		from  time.Time
+		to    time.Time
+		want  []time.Time
+	}{
+		{
+			name:  "BYDAY и COUNT",
+			rule:  "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=4",
+			start: date(2024, time.January, 1, 10),
+			from:  date(2024, time.January, 1, 0),
+			to:    date(2024, time.February, 1, 0),
+			want: []time.Time{
+				date(2024, time.January, 1, 10),
+				date(2024, time.January, 3, 10),
+				date(2024, time.January, 8, 10),
+				date(2024, time.January, 10, 10),
+			},
+		},
+		{
+			name:  "COUNT учитывает повторения до начала интервала",
+			rule:  "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=4",
+			start: date(2024, time.January, 1, 10),
+			from:  date(2024, time.January, 5, 0),
+			to:    date(2024, time.February, 1, 0),
+			want: []time.Time{
+				date(2024, time.January, 8, 10),
+				date(2024, time.January, 10, 10),
+			},
+		},
+		{
+			name:  "начало вне BYDAY не входит в повторения",
+			rule:  "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=2",
+			start: date(2024, time.January, 2, 10),
+			from:  date(2024, time.January, 1, 0),
+			to:    date(2024, time.February, 1, 0),
+			want: []time.Time{
+				date(2024, time.January, 3, 10),
+				date(2024, time.January, 8, 10),
+			},
+		},
+		{
+			name:  "BYDAY с интервалом в две недели",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,TH;COUNT=4",
+			start: date(2024, time.January, 2, 9),
+			from:  date(2024, time.January, 1, 0),
+			to:    date(2024, time.March, 1, 0),
+			want: []time.Time{
+				date(2024, time.January, 2, 9),
+				date(2024, time.January, 4, 9),
+				date(2024, time.January, 16, 9),
+				date(2024, time.January, 18, 9),
+			},
+		},
+		{
+			name:  "UNTIL включает последний день",
+			rule:  "FREQ=DAILY;UNTIL=20240103",
+			start: date(2024, time.January, 1, 10),
+			from:  date(2024, time.January, 1, 0),
+			to:    date(2024, time.February, 1, 0),
+			want: []time.Time{
+				date(2024, time.January, 1, 10),
+				date(2024, time.January, 2, 10),
+				date(2024, time.January, 3, 10),
+			},
+		},
+		{
+			name:  "UNTIL в день еженедельного повторения",
+			rule:  "FREQ=WEEKLY;UNTIL=20240115",
+			start: date(2024, time.January, 1, 18),
+			from:  date(2024, time.January, 1, 0),
+			to:    date(2024, time.March, 1, 0),
+			want: []time.Time{
+				date(2024, time.January, 1, 18),
+				date(2024, time.January, 8, 18),
+				date(2024, time.January, 15, 18),
+			},
+		},
+		{
+			name:  "пропуск месяцев без 31-го числа",
+			rule:  "FREQ=MONTHLY;COUNT=4",
+			start: date(2024, time.January, 31, 12),
+			from:  date(2024, time.January, 1, 0),
+			to:    date(2025, time.January, 1, 0),
+			want: []time.Time{
+				date(2024, time.January, 31, 12),
+				date(2024, time.March, 31, 12),
+				date(2024, time.May, 31, 12),
+				date(2024, time.July, 31, 12),
+			},
+		},
+		{
+			name:  "пропуск февраля для 30-го числа",
+			rule:  "FREQ=MONTHLY",
+			start: date(2024, time.January, 30, 12),
+			from:  date(2024, time.January, 1, 0),
+			to:    date(2024, time.June, 1, 0),
+			want: []time.Time{
+				date(2024, time.January, 30, 12),
+				date(2024, time.March, 30, 12),
+				date(2024, time.April, 30, 12),
+				date(2024, time.May, 30, 12),
+			},
+		},
+		{
+			name:  "29 февраля только в високосные годы",
+			rule:  "FREQ=MONTHLY;INTERVAL=12",
+			start: date(2024, time.February, 29, 8),
+			from:  date(2024, time.January, 1, 0),
+			to:    date(2029, time.January, 1, 0),
+			want: []time.Time{
+				date(2024, time.February, 29, 8),
+				date(2028, time.February, 29, 8),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.rule, err)
+			}
+			got := rule.Between(tt.start, tt.from, tt.to)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Between() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) {
+					t.Errorf("Between()[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRuleNext(t *testing.T) {
+	rule, err := Parse("FREQ=WEEKLY;BYDAY=MO,WE;COUNT=3")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	start := date(2024, time.January, 1, 10)
+
+	tests := []struct {
+		after     time.Time
+		want      time.Time
+		wantFound bool
+	}{
+		{after: date(2023, time.December, 31, 0), want: date(2024, time.January, 1, 10), wantFound: true},
+		{after: date(2024, time.January, 1, 10), want: date(2024, time.January, 3, 10), wantFound: true},
+		{after: date(2024, time.January, 4, 0), want: date(2024, time.January, 8, 10), wantFound: true},
+		{after: date(2024, time.January, 8, 10), wantFound: false},
+	}
+	for _, tt := range tests {
+		got, found := rule.Next(start, tt.after)
+		if found != tt.wantFound || (found && !got.Equal(tt.want)) {
+			t.Errorf("Next(%v) = %v, %v, want %v, %v", tt.after, got, found, tt.want, tt.wantFound)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=YEARLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=-1",
+		"FREQ=DAILY;UNTIL=2024-01-01",
+		"FREQ=DAILY;BYDAY=MO",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=WEEKLY;FOO=1",
+	}
+	for _, input := range tests {
+		if _, err := Parse(input); err == nil {
+			t.Errorf("Parse(%q) не вернул ошибку", input)
+		}
+	}
+}
@@ -0,0 +1,505 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"aliorToDoBot/src/dateparse"
+	"aliorToDoBot/src/db"
+	gorm_models2 "aliorToDoBot/src/db/gorm_models"
+)
+
+// ---- Функционал задач ----
+
+var tempTask = make(map[int64]gorm_models2.Task) // Временное хранилище создаваемых задач
+
+var taskPriorityIcons = map[string]string{
+	gorm_models2.TaskPriorityHigh:   "🔴",
+	gorm_models2.TaskPriorityNormal: "🟡",
+	gorm_models2.TaskPriorityLow:    "🟢",
+}
+
+var taskPriorityLabels = map[string]string{
+	gorm_models2.TaskPriorityHigh:   "🔴 Высокий",
+	gorm_models2.TaskPriorityNormal: "🟡 Обычный",
+	gorm_models2.TaskPriorityLow:    "🟢 Низкий",
+}
+
+func sendTasksMenu(bot *tgbotapi.BotAPI, chatID int64) {
+	msg := tgbotapi.NewMessage(chatID, "Меню задач:")
+	msg.ReplyMarkup = tgbotapi.ReplyKeyboardMarkup{
+		Keyboard: [][]tgbotapi.KeyboardButton{
+			{tgbotapi.NewKeyboardButton("Создать задачу")},
+			{tgbotapi.NewKeyboardButton("Мои задачи"), tgbotapi.NewKeyboardButton("Назначено мне")},
+			{tgbotapi.NewKeyboardButton("Доска")},
+			{tgbotapi.NewKeyboardButton("Главное меню")},
+		},
+		ResizeKeyboard: true,
+	}
+	bot.Send(msg)
+}
+
+// isTaskOverdue сообщает, просрочена ли невыполненная задача
+func isTaskOverdue(task gorm_models2.Task, now time.Time) bool {
+	return !task.IsDone && task.Deadline != nil && task.Deadline.Before(now)
+}
+
+// formatTask форматирует задачу для показа в часовом поясе loc.
+// Просроченные задачи выделяются.
+func formatTask(task gorm_models2.Task, groupName string, loc *time.Location, now time.Time) string {
+	deadline := "без срока"
+	if task.Deadline != nil {
+		deadline = task.Deadline.In(loc).Format("02.01.2006 15:04")
+	}
+
+	result := fmt.Sprintf("%s *%s*\nГруппа: %s\nСрок: %s",
+		taskPriorityIcons[task.Priority],
+		tgbotapi.EscapeText(tgbotapi.ModeMarkdown, task.Title),
+		tgbotapi.EscapeText(tgbotapi.ModeMarkdown, groupName),
+		deadline)
+	if len(task.Assignees) > 0 {
+		names := make([]string, 0, len(task.Assignees))
+		for _, assignee := range task.Assignees {
+			names = append(names, tgbotapi.EscapeText(tgbotapi.ModeMarkdown, assignee.User.UserName))
+		}
+		result += "\nИсполнители: " + strings.Join(names, ", ")
+	}
+	if task.IsDone {
+		result += "\n✅ Выполнено"
+	} else if isTaskOverdue(task, now) {
+		result += "\n⏰ *Просрочено*"
+	}
+	return result
+}
+
+// startCreateTask предлагает выбрать группу для новой задачи
+func startCreateTask(bot *tgbotapi.BotAPI, chatID int64) {
+	groups, err := provider.GetUserGroups(context.Background(), chatID)
+	if err != nil {
+		log.Printf("Ошибка получения групп пользователя %d: %v", chatID, err)
+		bot.Send(tgbotapi.NewMessage(chatID, "Ошибка при получении ваших групп."))
+		return
+	}
+	if len(groups) == 0 {
+		bot.Send(tgbotapi.NewMessage(chatID, "Вы пока не состоите ни в одной группе."))
+		return
+	}
+
+	var inlineKeyboard [][]tgbotapi.InlineKeyboardButton
+	for _, group := range groups {
+		inlineKeyboard = append(inlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(group.GroupName, fmt.Sprintf("task_group_%d", group.IDGroup)),
+		))
+	}
+	msg := tgbotapi.NewMessage(chatID, "Выберите группу для задачи:")
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(inlineKeyboard...)
+	if _, err = bot.Send(msg); err != nil {
+		log.Printf("Ошибка отправки сообщения: %v", err)
+	}
+
+	userSteps[chatID] = "selecting_task_group"
+	log.Printf("Переход к состоянию: %s", userSteps[chatID])
+}
+
+// handleTaskCreation обрабатывает шаги создания задачи
+func handleTaskCreation(bot *tgbotapi.BotAPI, chatID int64, text string) {
+	if text == "Главное меню" {
+		delete(tempTask, chatID)
+		delete(userSteps, chatID)
+		sendMainMenu(bot, chatID)
+		return
+	}
+
+	task := tempTask[chatID]
+
+	switch userSteps[chatID] {
+	case "selecting_task_group":
+		bot.Send(tgbotapi.NewMessage(chatID, "Пожалуйста, выберите группу, нажав на кнопку."))
+
+	case "creating_task_title":
+		if strings.TrimSpace(text) == "" {
+			bot.Send(tgbotapi.NewMessage(chatID, "Название не может быть пустым."))
+			return
+		}
+		task.Title = strings.TrimSpace(text)
+		tempTask[chatID] = task
+
+		userSteps[chatID] = "creating_task_deadline"
+		msg := tgbotapi.NewMessage(chatID, "Введите срок выполнения, например «в пятницу 18:00», «завтра» или 25.12.2026. "+
+			"Если время не указано, срок — конец дня.")
+		msg.ReplyMarkup = tgbotapi.ReplyKeyboardMarkup{
+			Keyboard: [][]tgbotapi.KeyboardButton{
+				{tgbotapi.NewKeyboardButton("Без срока")},
+				{tgbotapi.NewKeyboardButton("Главное меню")},
+			},
+			ResizeKeyboard: true,
+		}
+		bot.Send(msg)
+
+	case "creating_task_deadline":
+		task.Deadline = nil
+		if text != "Без срока" {
+			loc := userLocation(chatID)
+			deadline, hasTime, err := dateparse.Parse(text, time.Now().In(loc))
+			if err != nil {
+				bot.Send(tgbotapi.NewMessage(chatID, "Не удалось распознать срок: "+err.Error()+
+					". Пример: «завтра 18:00» или 25.12.2026."))
+				return
+			}
+			if !hasTime {
+				year, month, day := deadline.Date()
+				deadline = time.Date(year, month, day, 23, 59, 0, 0, loc)
+			}
+			task.Deadline = &deadline
+		}
+		tempTask[chatID] = task
+
+		userSteps[chatID] = "creating_task_priority"
+		msg := tgbotapi.NewMessage(chatID, "Выберите приоритет задачи:")
+		msg.ReplyMarkup = tgbotapi.ReplyKeyboardMarkup{
+			Keyboard: [][]tgbotapi.KeyboardButton{
+				{
+					tgbotapi.NewKeyboardButton(taskPriorityLabels[gorm_models2.TaskPriorityHigh]),
+					tgbotapi.NewKeyboardButton(taskPriorityLabels[gorm_models2.TaskPriorityNormal]),
+					tgbotapi.NewKeyboardButton(taskPriorityLabels[gorm_models2.TaskPriorityLow]),
+				},
+				{tgbotapi.NewKeyboardButton("Главное меню")},
+			},
+			ResizeKeyboard: true,
+		}
+		bot.Send(msg)
+
+	case "creating_task_priority":
+		task.Priority = ""
+		for priority, label := range taskPriorityLabels {
+			if text == label {
+				task.Priority = priority
+			}
+		}
+		if task.Priority == "" {
+			bot.Send(tgbotapi.NewMessage(chatID, "Пожалуйста, выберите приоритет одной из кнопок."))
+			return
+		}
+
+		if err := provider.CreateTask(context.Background(), chatID, task.IDGroup, task.Title, task.Priority, task.Deadline); err != nil {
+			log.Printf("Ошибка создания задачи: %v", err)
+			bot.Send(tgbotapi.NewMessage(chatID, "Не удалось создать задачу: "+err.Error()))
+			return
+		}
+		delete(tempTask, chatID)
+		delete(userSteps, chatID)
+		bot.Send(tgbotapi.NewMessage(chatID, "Задача успешно создана!"))
+		sendTasksMenu(bot, chatID)
+	}
+}
+
+// viewMyTasks показывает невыполненные задачи всех групп пользователя
+func viewMyTasks(bot *tgbotapi.BotAPI, chatID int64) {
+	tasks, err := provider.GetUserTasks(context.Background(), chatID, false)
+	if err != nil {
+		log.Printf("Ошибка получения задач пользователя %d: %v", chatID, err)
+		bot.Send(tgbotapi.NewMessage(chatID, "Ошибка при получении ваших задач."))
+		return
+	}
+	if len(tasks) == 0 {
+		bot.Send(tgbotapi.NewMessage(chatID, "Невыполненных задач нет."))
+		return
+	}
+	sendTaskList(bot, chatID, "Ваши задачи", tasks)
+}
+
+// viewAssignedTasks показывает невыполненные задачи, назначенные пользователю
+func viewAssignedTasks(bot *tgbotapi.BotAPI, chatID int64) {
+	tasks, err := provider.GetAssignedTasks(context.Background(), chatID)
+	if err != nil {
+		log.Printf("Ошибка получения назначенных задач пользователя %d: %v", chatID, err)
+		bot.Send(tgbotapi.NewMessage(chatID, "Ошибка при получении назначенных вам задач."))
+		return
+	}
+	if len(tasks) == 0 {
+		bot.Send(tgbotapi.NewMessage(chatID, "Вам пока не назначено ни одной задачи."))
+		return
+	}
+	sendTaskList(bot, chatID, "Назначено вам", tasks)
+}
+
+// sendTaskList отправляет список задач и кнопки для их выполнения.
+// Администраторам групп доступна кнопка назначения исполнителей.
+func sendTaskList(bot *tgbotapi.BotAPI, chatID int64, title string, tasks []gorm_models2.Task) {
+	adminGroups, err := provider.GetAdminGroups(context.Background(), chatID)
+	if err != nil {
+		log.Printf("Ошибка получения групп пользователя %d: %v", chatID, err)
+	}
+	isAdmin := make(map[int64]bool)
+	for _, group := range adminGroups {
+		isAdmin[group.IDGroup] = true
+	}
+
+	groupIDs := make([]int64, 0, len(tasks))
+	for _, task := range tasks {
+		groupIDs = append(groupIDs, task.IDGroup)
+	}
+	var groups []gorm_models2.Group
+	if err = db.DB.Where("id_group IN ?", groupIDs).Find(&groups).Error; err != nil {
+		log.Println("Ошибка получения данных групп:", err)
+	}
+	groupMap := make(map[int64]string)
+	for _, group := range groups {
+		groupMap[group.IDGroup] = group.GroupName
+	}
+
+	loc := userLocation(chatID)
+	now := time.Now()
+
+	overdue := 0
+	for _, task := range tasks {
+		if isTaskOverdue(task, now) {
+			overdue++
+		}
+	}
+
+	var message strings.Builder
+	if overdue > 0 {
+		message.WriteString(fmt.Sprintf("%s (просрочено: %d):\n\n", title, overdue))
+	} else {
+		message.WriteString(title + ":\n\n")
+	}
+	var inlineKeyboard [][]tgbotapi.InlineKeyboardButton
+	for _, task := range tasks {
+		// Задачи, не поместившиеся в сообщение, не получают и кнопок
+		if !appendPageBlock(&message, formatTask(task, groupMap[task.IDGroup], loc, now)+"\n\n") {
+			break
+		}
+
+		row := tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✅ "+task.Title, fmt.Sprintf("task_done_%d", task.IDTask)),
+		)
+		if isAdmin[task.IDGroup] {
+			row = append(row, tgbotapi.NewInlineKeyboardButtonData("👤", fmt.Sprintf("task_assign_%d", task.IDTask)))
+		}
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData("🗑", fmt.Sprintf("task_del_%d", task.IDTask)))
+		inlineKeyboard = append(inlineKeyboard, row)
+	}
+
+	msg := tgbotapi.NewMessage(chatID, message.String())
+	msg.ParseMode = "Markdown"
+	bot.Send(msg)
+
+	doneMsg := tgbotapi.NewMessage(chatID, "Нажмите на задачу, чтобы отметить её выполненной, или 👤, чтобы назначить исполнителей:")
+	doneMsg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(inlineKeyboard...)
+	bot.Send(doneMsg)
+}
+
+// handleTaskCallback обрабатывает инлайн-кнопки задач
+func handleTaskCallback(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+	data := callback.Data
+
+	if data == "task_cancel_del" {
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Удаление отменено."))
+		return
+	}
+	if strings.HasPrefix(data, "task_assignee_") {
+		handleTaskAssigneeToggle(bot, callback)
+		return
+	}
+
+	var prefix string
+	for _, p := range []string{"task_group_", "task_done_", "task_del_", "task_confirm_del_", "task_assign_"} {
+		if strings.HasPrefix(data, p) {
+			prefix = p
+			break
+		}
+	}
+	id, err := strconv.ParseInt(strings.TrimPrefix(data, prefix), 10, 64)
+	if prefix == "" || err != nil {
+		log.Printf("Некорректные данные кнопки задачи: %s", data)
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Неизвестное действие"))
+		return
+	}
+
+	switch prefix {
+	case "task_group_":
+		if userSteps[chatID] != "selecting_task_group" {
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Создание задачи уже завершено."))
+			return
+		}
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Группа выбрана!"))
+		tempTask[chatID] = gorm_models2.Task{IDGroup: id}
+		userSteps[chatID] = "creating_task_title"
+		msg := tgbotapi.NewMessage(chatID, "Введите название задачи:")
+		msg.ReplyMarkup = tgbotapi.ReplyKeyboardMarkup{
+			Keyboard: [][]tgbotapi.KeyboardButton{
+				{tgbotapi.NewKeyboardButton("Главное меню")},
+			},
+			ResizeKeyboard: true,
+		}
+		bot.Send(msg)
+
+	case "task_done_":
+		if err = provider.CompleteTask(context.Background(), chatID, id); err != nil {
+			log.Printf("Ошибка выполнения задачи ID %d: %v", id, err)
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Не удалось отметить задачу: "+err.Error()))
+			return
+		}
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Задача выполнена!"))
+		viewMyTasks(bot, chatID)
+
+	case "task_del_":
+		task, err := provider.GetTask(context.Background(), id)
+		if err != nil {
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Задача не найдена."))
+			return
+		}
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("Удалить задачу '%s'?", task.Title))
+		msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+			tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData("Да", fmt.Sprintf("task_confirm_del_%d", id)),
+				tgbotapi.NewInlineKeyboardButtonData("Нет", "task_cancel_del"),
+			),
+		)
+		bot.Send(msg)
+
+	case "task_assign_":
+		text, keyboard, err := taskAssigneePicker(id)
+		if err != nil {
+			log.Printf("Ошибка получения участников для задачи ID %d: %v", id, err)
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Не удалось открыть список участников."))
+			return
+		}
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		msg := tgbotapi.NewMessage(chatID, text)
+		msg.ReplyMarkup = keyboard
+		bot.Send(msg)
+
+	case "task_confirm_del_":
+		if err = provider.DeleteTask(context.Background(), chatID, id); err != nil {
+			log.Printf("Ошибка удаления задачи ID %d: %v", id, err)
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Не удалось удалить задачу: "+err.Error()))
+			return
+		}
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Задача удалена."))
+		viewMyTasks(bot, chatID)
+	}
+}
+
+// taskAssigneePicker формирует список участников группы задачи.
+// Нажатие на участника назначает его исполнителем или снимает назначение.
+func taskAssigneePicker(taskID int64) (string, tgbotapi.InlineKeyboardMarkup, error) {
+	ctx := context.Background()
+
+	task, err := provider.GetTask(ctx, taskID)
+	if err != nil {
+		return "", tgbotapi.InlineKeyboardMarkup{}, err
+	}
+	members, err := provider.GetGroupMembers(ctx, task.IDGroup)
+	if err != nil {
+		return "", tgbotapi.InlineKeyboardMarkup{}, err
+	}
+
+	assigned := make(map[int64]bool)
+	for _, assignee := range task.Assignees {
+		assigned[assignee.IDUser] = true
+	}
+
+	var inlineKeyboard [][]tgbotapi.InlineKeyboardButton
+	for _, member := range members {
+		mark := "⬜"
+		if assigned[member.IDUser] {
+			mark = "☑️"
+		}
+		inlineKeyboard = append(inlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(mark+" "+member.UserName,
+				fmt.Sprintf("task_assignee_%d_%d", taskID, member.IDUser)),
+		))
+	}
+	text := fmt.Sprintf("Исполнители задачи «%s». Нажмите на участника, чтобы назначить его или снять назначение:", task.Title)
+	return text, tgbotapi.NewInlineKeyboardMarkup(inlineKeyboard...), nil
+}
+
+// handleTaskAssigneeToggle назначает участника исполнителем или снимает назначение,
+// обновляет список на месте и сообщает новому исполнителю о задаче
+func handleTaskAssigneeToggle(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+
+	// Данные вида task_assignee_<IDTask>_<IDUser>
+	taskPart, userPart, found := strings.Cut(strings.TrimPrefix(callback.Data, "task_assignee_"), "_")
+	taskID, err := strconv.ParseInt(taskPart, 10, 64)
+	if err != nil || !found {
+		log.Printf("Некорректные данные кнопки исполнителя: %s", callback.Data)
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Неизвестное действие"))
+		return
+	}
+	userID, err := strconv.ParseInt(userPart, 10, 64)
+	if err != nil {
+		log.Printf("Некорректные данные кнопки исполнителя: %s", callback.Data)
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Неизвестное действие"))
+		return
+	}
+
+	ctx := context.Background()
+	assigned, err := provider.ToggleTaskAssignee(ctx, chatID, taskID, userID)
+	if err != nil {
+		log.Printf("Ошибка назначения исполнителя задачи ID %d: %v", taskID, err)
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Не удалось изменить исполнителей: "+err.Error()))
+		return
+	}
+	if assigned {
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Исполнитель назначен."))
+		notifyTaskAssignee(bot, taskID, userID)
+	} else {
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Назначение снято."))
+	}
+
+	text, keyboard, err := taskAssigneePicker(taskID)
+	if err != nil {
+		log.Printf("Ошибка получения участников для задачи ID %d: %v", taskID, err)
+		return
+	}
+	edit := tgbotapi.NewEditMessageTextAndMarkup(chatID, callback.Message.MessageID, text, keyboard)
+	if _, err = bot.Send(edit); err != nil {
+		log.Printf("Ошибка обновления списка исполнителей: %v", err)
+	}
+}
+
+// notifyTaskAssignee отправляет исполнителю личное сообщение о назначенной задаче
+func notifyTaskAssignee(bot *tgbotapi.BotAPI, taskID int64, userID int64) {
+	task, err := provider.GetTask(context.Background(), taskID)
+	if err != nil {
+		log.Printf("Ошибка получения задачи ID %d: %v", taskID, err)
+		return
+	}
+
+	var user gorm_models2.User
+	for _, assignee := range task.Assignees {
+		if assignee.IDUser == userID {
+			user = assignee.User
+		}
+	}
+	if user.IDChat == 0 {
+		log.Printf("Исполнитель %d задачи ID %d не найден", userID, taskID)
+		return
+	}
+
+	var group gorm_models2.Group
+	if err = db.DB.First(&group, task.IDGroup).Error; err != nil {
+		log.Printf("Ошибка получения группы с ID %d: %v", task.IDGroup, err)
+	}
+
+	loc := loadLocation(user.TimeZone)
+	msg := tgbotapi.NewMessage(user.IDChat, "📌 Вам назначена задача:\n\n"+formatTask(task, group.GroupName, loc, time.Now()))
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("✅ Выполнено", fmt.Sprintf("task_done_%d", task.IDTask)),
+	))
+	if _, err = bot.Send(msg); err != nil {
+		log.Printf("Ошибка отправки уведомления исполнителю %d: %v", userID, err)
+	}
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"aliorToDoBot/src/db"
+	gorm_models2 "aliorToDoBot/src/db/gorm_models"
+)
+
+// ---- Функционал шаблонов мероприятий ----
+
+// templateDraft мероприятие, которое пользователь сохраняет как шаблон
+type templateDraft struct {
+	IDEvent int64
+	Shared  bool
+}
+
+var templateDrafts = make(map[int64]templateDraft)
+
+// startSaveTemplate спрашивает, для кого сохранить мероприятие как шаблон.
+// Сохранять шаблоны может только администратор: создавать мероприятия группы по ним может только он.
+func startSaveTemplate(bot *tgbotapi.BotAPI, chatID int64, eventID int64) {
+	ctx := context.Background()
+	var event gorm_models2.Event
+	if err := db.DB.First(&event, eventID).Error; err != nil {
+		log.Printf("Ошибка получения мероприятия: %v", err)
+		bot.Send(tgbotapi.NewMessage(chatID, "Мероприятие не найдено."))
+		return
+	}
+	if err := provider.CheckGroupMember(ctx, chatID, event.IDGroup); err != nil {
+		bot.Send(tgbotapi.NewMessage(chatID, "Мероприятие не найдено."))
+		return
+	}
+
+	isAdmin, err := provider.IsGroupAdmin(ctx, chatID, event.IDGroup)
+	if err != nil {
+		log.Printf("Ошибка проверки прав пользователя %d: %v", chatID, err)
+		bot.Send(tgbotapi.NewMessage(chatID, "Произошла ошибка. Попробуйте позже."))
+		return
+	}
+	if !isAdmin {
+		bot.Send(tgbotapi.NewMessage(chatID, "Сохранять шаблоны мероприятий группы может только её администратор."))
+		return
+	}
+	row := tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("👤 Только для меня", fmt.Sprintf("tpl_scope_%d_u", eventID)),
+		tgbotapi.NewInlineKeyboardButtonData("👥 Для группы", fmt.Sprintf("tpl_scope_%d_g", eventID)),
+	)
+
+	msg := tgbotapi.NewMessage(chatID, "Шаблон сохранит название, категорию, продолжительность, ссылку и место "+
+		"мероприятия «"+event.NameEvent+"». Кому он будет доступен?")
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(row)
+	bot.Send(msg)
+}
+
+// viewTemplates показывает доступные пользователю шаблоны
+func viewTemplates(bot *tgbotapi.BotAPI, chatID int64) {
+	templates, err := provider.GetUserTemplates(context.Background(), chatID)
+	if err != nil {
+		log.Printf("Ошибка получения шаблонов пользователя %d: %v", chatID, err)
+		bot.Send(tgbotapi.NewMessage(chatID, "Ошибка при получении шаблонов."))
+		return
+	}
+	if len(templates) == 0 {
+		bot.Send(tgbotapi.NewMessage(chatID, "Шаблонов пока нет. Откройте мероприятие и нажмите «📋 В шаблон», чтобы создать шаблон."))
+		return
+	}
+
+	var inlineKeyboard [][]tgbotapi.InlineKeyboardButton
+	for _, template := range templates {
+		label := "👥 " + template.Name
+		if template.IDUser != nil {
+			label = "👤 " + template.Name
+		}
+		inlineKeyboard = append(inlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(label, fmt.Sprintf("tpl_use_%d", template.IDTemplate)),
+			tgbotapi.NewInlineKeyboardButtonData("🗑", fmt.Sprintf("tpl_del_%d", template.IDTemplate)),
+		))
+	}
+	msg := tgbotapi.NewMessage(chatID, "Выберите шаблон. 👤 — личный, 👥 — общий для группы:")
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(inlineKeyboard...)
+	bot.Send(msg)
+}
+
+// startEventFromTemplate заполняет мастер создания мероприятия по шаблону и спрашивает только дату
+func startEventFromTemplate(bot *tgbotapi.BotAPI, chatID int64, templateID int64) {
+	ctx := context.Background()
+	template, err := provider.GetTemplate(ctx, chatID, templateID)
+	if err != nil {
+		log.Printf("Ошибка получения шаблона ID %d: %v", templateID, err)
+		bot.Send(tgbotapi.NewMessage(chatID, "Не удалось открыть шаблон: "+err.Error()))
+		return
+	}
+
+	isAdmin, err := provider.IsGroupAdmin(ctx, chatID, template.IDGroup)
+	if err != nil {
+		log.Printf("Ошибка проверки прав пользователя %d: %v", chatID, err)
+		bot.Send(tgbotapi.NewMessage(chatID, "Произошла ошибка. Попробуйте позже."))
+		return
+	}
+	if !isAdmin {
+		bot.Send(tgbotapi.NewMessage(chatID, "Создавать мероприятия в группе шаблона может только её администратор."))
+		return
+	}
+
+	// Категорию шаблона могли удалить из группы после его сохранения
+	categories, err := provider.GetGroupCategories(ctx, template.IDGroup)
+	if err != nil {
+		log.Printf("Ошибка получения категорий группы %d: %v", template.IDGroup, err)
+		bot.Send(tgbotapi.NewMessage(chatID, "Произошла ошибка. Попробуйте позже."))
+		return
+	}
+	categoryExists := false
+	for _, category := range categories {
+		if category.Name == template.Category {
+			categoryExists = true
+			break
+		}
+	}
+	if !categoryExists {
+		bot.Send(tgbotapi.NewMessage(chatID, "Категории «"+template.Category+"» из шаблона больше нет в группе. "+
+			"Удалите шаблон и сохраните новый из мероприятия с актуальной категорией."))
+		return
+	}
+
+	tempEvent[chatID] = gorm_models2.Event{
+		NameEvent:     template.NameEvent,
+		IDGroup:       template.IDGroup,
+		Category:      template.Category,
+		Duration:      template.Duration,
+		IsAllDay:      template.IsAllDay,
+		LinkToVideo:   template.LinkToVideo,
+		EventLocation: template.EventLocation,
+	}
+	tempEventTemplate[chatID] = templateID
+	delete(tempEventReminders, chatID)
+	delete(rescheduledEvent, chatID)
+
+	if template.IsAllDay {
+		userSteps[chatID] = "creating_event_all_day_date"
+		sendInputPrompt(bot, chatID, "Шаблон «"+template.Name+"». Введите дату мероприятия, например «25 декабря» или дд.мм.гггг:")
+		sendDatePicker(bot, chatID)
+		return
+	}
+	askEventTime(bot, chatID, "Шаблон «"+template.Name+"». Когда начало? Например: «завтра в 15:00» или дд.мм.гггг чч:мм:")
+}
+
+// handleTemplateCallback обрабатывает кнопки шаблонов
+func handleTemplateCallback(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+	data := callback.Data
+
+	if strings.HasPrefix(data, "tpl_scope_") {
+		// Данные вида tpl_scope_<IDEvent>_<u|g>
+		parts := strings.Split(strings.TrimPrefix(data, "tpl_scope_"), "_")
+		eventID, err := strconv.ParseInt(parts[0], 10, 64)
+		if len(parts) != 2 || err != nil {
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Неизвестное действие"))
+			return
+		}
+		var event gorm_models2.Event
+		if err = db.DB.First(&event, eventID).Error; err != nil {
+			log.Printf("Ошибка получения мероприятия: %v", err)
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Мероприятие не найдено."))
+			return
+		}
+		if err = provider.CheckGroupMember(context.Background(), chatID, event.IDGroup); err != nil {
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Мероприятие не найдено."))
+			return
+		}
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+
+		templateDrafts[chatID] = templateDraft{IDEvent: eventID, Shared: parts[1] == "g"}
+		userSteps[chatID] = "naming_template"
+		msg := tgbotapi.NewMessage(chatID, "Введите название шаблона:")
+		msg.ReplyMarkup = tgbotapi.ReplyKeyboardMarkup{
+			Keyboard: [][]tgbotapi.KeyboardButton{
+				{tgbotapi.NewKeyboardButton(event.NameEvent)},
+				{tgbotapi.NewKeyboardButton("Главное меню")},
+			},
+			ResizeKeyboard: true,
+		}
+		bot.Send(msg)
+		return
+	}
+
+	var prefix string
+	for _, p := range []string{"tpl_save_", "tpl_use_", "tpl_del_"} {
+		if strings.HasPrefix(data, p) {
+			prefix = p
+			break
+		}
+	}
+	id, err := strconv.ParseInt(strings.TrimPrefix(data, prefix), 10, 64)
+	if prefix == "" || err != nil {
+		log.Printf("Некорректные данные кнопки шаблона: %s", data)
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Неизвестное действие"))
+		return
+	}
+
+	switch prefix {
+	case "tpl_save_":
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		startSaveTemplate(bot, chatID, id)
+
+	case "tpl_use_":
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		startEventFromTemplate(bot, chatID, id)
+
+	case "tpl_del_":
+		if err = provider.DeleteTemplate(context.Background(), chatID, id); err != nil {
+			log.Printf("Ошибка удаления шаблона ID %d: %v", id, err)
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Не удалось удалить шаблон: "+err.Error()))
+			return
+		}
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Шаблон удалён."))
+		viewTemplates(bot, chatID)
+	}
+}
+
+// handleTemplateNameInput сохраняет мероприятие как шаблон с введённым названием
+func handleTemplateNameInput(bot *tgbotapi.BotAPI, chatID int64, text string) {
+	draft, ok := templateDrafts[chatID]
+	if !ok || text == "Главное меню" {
+		delete(templateDrafts, chatID)
+		delete(userSteps, chatID)
+		sendMainMenu(bot, chatID)
+		return
+	}
+
+	if err := provider.CreateTemplateFromEvent(context.Background(), chatID, draft.IDEvent, text, draft.Shared); err != nil {
+		log.Printf("Ошибка сохранения шаблона из мероприятия ID %d: %v", draft.IDEvent, err)
+		bot.Send(tgbotapi.NewMessage(chatID, "Не удалось сохранить шаблон: "+err.Error()))
+		return
+	}
+
+	delete(templateDrafts, chatID)
+	delete(userSteps, chatID)
+	bot.Send(tgbotapi.NewMessage(chatID, "Шаблон «"+strings.TrimSpace(text)+"» сохранён. Создать мероприятие по нему можно кнопкой «Из шаблона»."))
+	sendEventsMenu(bot, chatID)
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	gorm_models2 "aliorToDoBot/src/db/gorm_models"
+)
+
+// ---- Функционал часовых поясов ----
+
+// Часовые пояса, которые предлагаются кнопками
+var timeZoneChoices = []struct {
+	title string
+	name  string
+}{
+	{"Калининград (UTC+2)", "Europe/Kaliningrad"},
+	{"Москва (UTC+3)", "Europe/Moscow"},
+	{"Самара (UTC+4)", "Europe/Samara"},
+	{"Екатеринбург (UTC+5)", "Asia/Yekaterinburg"},
+	{"Омск (UTC+6)", "Asia/Omsk"},
+	{"Новосибирск (UTC+7)", "Asia/Novosibirsk"},
+	{"Иркутск (UTC+8)", "Asia/Irkutsk"},
+	{"Якутск (UTC+9)", "Asia/Yakutsk"},
+	{"Владивосток (UTC+10)", "Asia/Vladivostok"},
+	{"Магадан (UTC+11)", "Asia/Magadan"},
+	{"Камчатка (UTC+12)", "Asia/Kamchatka"},
+}
+
+var reUTCOffset = regexp.MustCompile(`(?i)^(?:utc|gmt)?\s*([+-])\s*(\d{1,2})$`)
+
+// loadLocation возвращает часовой пояс по имени, а если он неизвестен — часовой пояс по умолчанию
+func loadLocation(name string) *time.Location {
+	if name != "" {
+		if loc, err := time.LoadLocation(name); err == nil {
+			return loc
+		}
+		log.Printf("Неизвестный часовой пояс '%s', используется %s", name, gorm_models2.DefaultTimeZone)
+	}
+	loc, err := time.LoadLocation(gorm_models2.DefaultTimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// userLocation возвращает часовой пояс пользователя
+func userLocation(chatID int64) *time.Location {
+	name, err := provider.GetUserTimeZone(context.Background(), chatID)
+	if err != nil {
+		log.Printf("Ошибка получения часового пояса пользователя %d: %v", chatID, err)
+	}
+	return loadLocation(name)
+}
+
+// eventLocation возвращает часовой пояс, в котором мероприятие было создано.
+// В нём считаются повторения и даты мероприятий на весь день.
+func eventLocation(event gorm_models2.Event) *time.Location {
+	return loadLocation(event.TimeZone)
+}
+
+// parseTimeZoneInput принимает имя часового пояса (Europe/Moscow) или смещение (UTC+3)
+func parseTimeZoneInput(text string) (string, error) {
+	text = strings.TrimSpace(text)
+	if m := reUTCOffset.FindStringSubmatch(text); m != nil {
+		hours, _ := strconv.Atoi(m[2])
+		if hours > 14 {
+			return "", fmt.Errorf("смещение должно быть не больше 14 часов")
+		}
+		if hours == 0 {
+			return "UTC", nil
+		}
+		// В базе IANA знак у зон Etc/GMT обратный: UTC+3 это Etc/GMT-3
+		sign := "-"
+		if m[1] == "-" {
+			sign = "+"
+		}
+		return fmt.Sprintf("Etc/GMT%s%d", sign, hours), nil
+	}
+	if text == "" || text == "Local" {
+		return "", fmt.Errorf("неизвестный часовой пояс")
+	}
+	if _, err := time.LoadLocation(text); err != nil {
+		return "", fmt.Errorf("неизвестный часовой пояс: %s", text)
+	}
+	return text, nil
+}
+
+// askTimeZone показывает текущий часовой пояс пользователя и предлагает выбрать другой
+func askTimeZone(bot *tgbotapi.BotAPI, chatID int64) {
+	loc := userLocation(chatID)
+
+	var inlineKeyboard [][]tgbotapi.InlineKeyboardButton
+	for _, choice := range timeZoneChoices {
+		inlineKeyboard = append(inlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(choice.title, "tz_"+choice.name),
+		))
+	}
+	inlineKeyboard = append(inlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("Другой…", "tz_manual"),
+	))
+
+	msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("Ваш часовой пояс: %s (сейчас %s).\n"+
+		"Все даты мероприятий показываются и вводятся в нём. Чтобы изменить, выберите другой:",
+		loc.String(), time.Now().In(loc).Format("15:04")))
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(inlineKeyboard...)
+	if _, err := bot.Send(msg); err != nil {
+		log.Printf("Ошибка отправки сообщения: %v", err)
+	}
+}
+
+// handleTimeZoneCallback сохраняет часовой пояс, выбранный кнопкой, или запрашивает его вручную
+func handleTimeZoneCallback(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+	bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+
+	if callback.Data == "tz_manual" {
+		userSteps[chatID] = "setting_time_zone"
+		msg := tgbotapi.NewMessage(chatID, "Введите часовой пояс, например Europe/Berlin или UTC+5:")
+		msg.ReplyMarkup = tgbotapi.ReplyKeyboardMarkup{
+			Keyboard: [][]tgbotapi.KeyboardButton{
+				{tgbotapi.NewKeyboardButton("Главное меню")},
+			},
+			ResizeKeyboard: true,
+		}
+		bot.Send(msg)
+		return
+	}
+	saveTimeZone(bot, chatID, strings.TrimPrefix(callback.Data, "tz_"))
+}
+
+// handleTimeZoneInput сохраняет введённый текстом часовой пояс
+func handleTimeZoneInput(bot *tgbotapi.BotAPI, chatID int64, text string) {
+	if text == "Главное меню" {
+		delete(userSteps, chatID)
+		sendMainMenu(bot, chatID)
+		return
+	}
+
+	name, err := parseTimeZoneInput(text)
+	if err != nil {
+		bot.Send(tgbotapi.NewMessage(chatID, "Не удалось распознать часовой пояс: "+err.Error()+
+			"\nВведите название, например Europe/Moscow, или смещение, например UTC+3."))
+		return
+	}
+	saveTimeZone(bot, chatID, name)
+}
+
+func saveTimeZone(bot *tgbotapi.BotAPI, chatID int64, name string) {
+	if err := provider.SetUserTimeZone(context.Background(), chatID, name); err != nil {
+		log.Printf("Ошибка сохранения часового пояса пользователя %d: %v", chatID, err)
+		bot.Send(tgbotapi.NewMessage(chatID, "Не удалось сохранить часовой пояс: "+err.Error()))
+		return
+	}
+
+	loc := loadLocation(name)
+	bot.Send(tgbotapi.NewMessage(chatID, fmt.Sprintf("Часовой пояс сохранён: %s. Сейчас у вас %s.",
+		loc.String(), time.Now().In(loc).Format("02.01.2006 15:04"))))
+	if userSteps[chatID] == "setting_time_zone" {
+		delete(userSteps, chatID)
+		sendSettingsMenu(bot, chatID)
+	}
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"aliorToDoBot/src/config"
+	"aliorToDoBot/src/db"
+	gorm_models2 "aliorToDoBot/src/db/gorm_models"
+)
+
+// ---- Функционал корзины удалённых мероприятий ----
+
+// startTrashPurger периодически окончательно удаляет мероприятия, которые пролежали в корзине дольше срока хранения
+func startTrashPurger(ctx context.Context, cfg *config.TrashConfig) {
+	ticker := time.NewTicker(cfg.PurgeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := provider.PurgeTrash(ctx, time.Now().Add(-cfg.Retention))
+			if err != nil {
+				log.Printf("Ошибка очистки корзины: %v", err)
+				continue
+			}
+			if purged > 0 {
+				log.Printf("Из корзины окончательно удалено мероприятий: %d", purged)
+			}
+		}
+	}
+}
+
+// trashEvent переносит мероприятие в корзину и присылает сообщение с кнопкой отмены удаления
+func trashEvent(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery, eventID int64) {
+	chatID := callback.Message.Chat.ID
+
+	var event gorm_models2.Event
+	if err := db.DB.First(&event, eventID).Error; err != nil {
+		log.Printf("Ошибка получения мероприятия: %v", err)
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Мероприятие не найдено."))
+		return
+	}
+	if err := provider.TrashEvent(context.Background(), chatID, eventID); err != nil {
+		log.Printf("Ошибка удаления мероприятия ID %d: %v", eventID, err)
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Не удалось удалить мероприятие: "+err.Error()))
+		return
+	}
+	bot.Request(tgbotapi.NewCallback(callback.ID, "Мероприятие перенесено в корзину."))
+
+	msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("Мероприятие «%s» перенесено в корзину. "+
+		"Отменить удаление можно в течение %s, позже — восстановить из раздела «Корзина».",
+		event.NameEvent, formatDuration(trashConfig.UndoWindow)))
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("↩️ Отменить", fmt.Sprintf("trash_undo_%d", eventID)),
+	))
+	if _, err := bot.Send(msg); err != nil {
+		log.Printf("Ошибка отправки сообщения: %v", err)
+	}
+	viewMyEvents(bot, chatID)
+}
+
+// viewTrash показывает удалённые мероприятия групп, в которых пользователь администратор
+func viewTrash(bot *tgbotapi.BotAPI, chatID int64) {
+	events, err := provider.GetTrash(context.Background(), chatID)
+	if err != nil {
+		log.Printf("Ошибка получения корзины пользователя %d: %v", chatID, err)
+		bot.Send(tgbotapi.NewMessage(chatID, "Ошибка при получении корзины."))
+		return
+	}
+	if len(events) == 0 {
+		bot.Send(tgbotapi.NewMessage(chatID, "Корзина пуста."))
+		return
+	}
+
+	loc := userLocation(chatID)
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("🗑 Корзина. Мероприятия удаляются навсегда через %s после удаления.\n",
+		formatDuration(trashConfig.Retention)))
+	var inlineKeyboard [][]tgbotapi.InlineKeyboardButton
+	for _, event := range events {
+		text.WriteString(fmt.Sprintf("\n• %s — удалено %s", event.NameEvent,
+			event.DeletedAt.Time.In(loc).Format("02.01.2006 15:04")))
+		inlineKeyboard = append(inlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("↩️ "+event.NameEvent, fmt.Sprintf("trash_restore_%d", event.IDEvent)),
+			tgbotapi.NewInlineKeyboardButtonData("❌", fmt.Sprintf("trash_purge_%d", event.IDEvent)),
+		))
+	}
+
+	msg := tgbotapi.NewMessage(chatID, text.String())
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(inlineKeyboard...)
+	if _, err = bot.Send(msg); err != nil {
+		log.Printf("Ошибка отправки сообщения: %v", err)
+	}
+}
+
+// handleTrashCallback обрабатывает отмену удаления и кнопки корзины
+func handleTrashCallback(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+	data := callback.Data
+	ctx := context.Background()
+
+	var prefix string
+	for _, p := range []string{"trash_undo_", "trash_restore_", "trash_purge_", "trash_purgeok_"} {
+		if strings.HasPrefix(data, p) {
+			prefix = p
+			break
+		}
+	}
+	eventID, err := strconv.ParseInt(strings.TrimPrefix(data, prefix), 10, 64)
+	if prefix == "" || err != nil {
+		log.Printf("Некорректные данные кнопки корзины: %s", data)
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Неизвестное действие"))
+		return
+	}
+
+	switch prefix {
+	case "trash_undo_":
+		if err = provider.RestoreEvent(ctx, chatID, eventID, time.Now().Add(-trashConfig.UndoWindow)); err != nil {
+			log.Printf("Ошибка отмены удаления мероприятия ID %d: %v", eventID, err)
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Не удалось отменить удаление: "+err.Error()))
+			return
+		}
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Удаление отменено."))
+		edit := tgbotapi.NewEditMessageText(chatID, callback.Message.MessageID, "Удаление отменено, мероприятие восстановлено.")
+		if _, err = bot.Send(edit); err != nil {
+			log.Printf("Ошибка обновления сообщения: %v", err)
+		}
+		viewMyEvents(bot, chatID)
+
+	case "trash_restore_":
+		if err = provider.RestoreEvent(ctx, chatID, eventID, time.Time{}); err != nil {
+			log.Printf("Ошибка восстановления мероприятия ID %d: %v", eventID, err)
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Не удалось восстановить мероприятие: "+err.Error()))
+			return
+		}
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Мероприятие восстановлено."))
+		viewTrash(bot, chatID)
+
+	case "trash_purge_":
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		msg := tgbotapi.NewMessage(chatID, "Удалить мероприятие навсегда? Восстановить его будет нельзя.")
+		msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("Да", fmt.Sprintf("trash_purgeok_%d", eventID)),
+		))
+		bot.Send(msg)
+
+	case "trash_purgeok_":
+		if err = provider.PurgeEvent(ctx, chatID, eventID); err != nil {
+			log.Printf("Ошибка окончательного удаления мероприятия ID %d: %v", eventID, err)
+			bot.Request(tgbotapi.NewCallback(callback.ID, "Не удалось удалить мероприятие: "+err.Error()))
+			return
+		}
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Мероприятие удалено навсегда."))
+		edit := tgbotapi.NewEditMessageText(chatID, callback.Message.MessageID, "Мероприятие удалено навсегда.")
+		if _, err = bot.Send(edit); err != nil {
+			log.Printf("Ошибка обновления сообщения: %v", err)
+		}
+		viewTrash(bot, chatID)
+	}
+}